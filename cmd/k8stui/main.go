@@ -1,15 +1,44 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/yourusername/k8stui/internal/k8s/app"
+	"k8s.io/klog/v2"
 )
 
 func main() {
+	// Parse command-line flags
+	var opts app.Options
+	var resource string
+	flag.StringVar(&opts.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+	flag.StringVar(&opts.Context, "context", "", "Kubeconfig context to use")
+	flag.StringVar(&opts.Namespace, "namespace", "", "Namespace to open at startup")
+	flag.StringVar(&resource, "resource", "", "Resource type to open at startup (e.g. pods, deployments, nodes)")
+	flag.BoolVar(&opts.ReadOnly, "readonly", false, "Disable all actions that modify the cluster")
+	flag.StringVar(&opts.ConfigFile, "config", "", "Path to the config file (defaults to "+app.DefaultConfigPath()+")")
+	flag.StringVar(&opts.LogDir, "log-dir", "", "Directory logs are saved under (defaults to ~/k8stui-logs)")
+	flag.BoolVar(&opts.GzipLogs, "gzip-logs", false, "Compress saved logs with gzip")
+	flag.Parse()
+
+	if resource != "" {
+		resourceType, err := app.ParseResourceType(resource)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		opts.Resource = resourceType
+	}
+
+	// Informers log watch errors through klog, which would garble the terminal UI
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
 	// Create a new app instance
-	appInstance := app.NewApp()
+	appInstance := app.NewAppWithOptions(opts)
 
 	// Run the application
 	if err := appInstance.Run(); err != nil {
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// logSourceColors are the colors of the pod/container tags of aggregated logs
+var logSourceColors = []string{"[#5fafff]", "[#87d787]", "[#ffaf5f]", "[#d787d7]", "[#5fd7d7]", "[#d7d75f]", "[#ff8787]", "[#afafff]"}
+
+// maxLogLineSize is the longest log line read from an aggregated stream
+const maxLogLineSize = 1024 * 1024
+
+// aggregatedStream is the log stream of a container of one of the aggregated pods
+type aggregatedStream struct {
+	cancel   context.CancelFunc
+	restarts int32 // Restart count of the container when the stream was opened
+}
+
+// logAggregator streams the logs of every container of the pods matching a selector into one
+// buffer, like stern. Streams are opened and cancelled as pods come and go.
+type logAggregator struct {
+	app       *App
+	client    kubernetes.Interface
+	source    string // Workload the pods belong to, as kind/name
+	namespace string
+	selector  labels.Selector
+	options   logOptions
+	buffer    *logBuffer
+	stopCh    chan struct{}
+	synced    atomic.Bool // Pods seen after the initial list are new, so their whole log is read
+
+	mu      sync.Mutex
+	streams map[string]*aggregatedStream // Open streams by pod/container
+	ended   map[string]int32             // Restart counts of the containers whose stream ended
+}
+
+// showWorkloadLogs streams the logs of the pods of the workload or service highlighted in the
+// resource table
+func (a *App) showWorkloadLogs() {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+	selector, err := podSelector(obj)
+	if err != nil {
+		a.showError(err.Error())
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	source := fmt.Sprintf("%s/%s", kind.Type, accessor.GetName())
+	if err := a.startAggregatedLogs(source, accessor.GetNamespace(), selector); err != nil {
+		a.showError(fmt.Sprintf("Error streaming logs of %s: %v", source, err))
+		return
+	}
+	a.CurrentFocus = 4
+	a.UpdateFocus()
+}
+
+// podSelector returns the selector of the pods run by a workload or backing a service
+func podSelector(obj runtime.Object) (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		selector = o.Spec.Selector
+	case *appsv1.StatefulSet:
+		selector = o.Spec.Selector
+	case *appsv1.DaemonSet:
+		selector = o.Spec.Selector
+	case *batchv1.Job:
+		selector = o.Spec.Selector
+	case *corev1.Service:
+		if len(o.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("service %s has no selector", o.Name)
+		}
+		return labels.SelectorFromSet(o.Spec.Selector), nil
+	default:
+		return nil, fmt.Errorf("logs can only be aggregated for deployments, statefulsets, daemonsets, jobs and services")
+	}
+
+	if selector == nil {
+		return nil, fmt.Errorf("no pod selector")
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod selector: %v", err)
+	}
+	if s.Empty() {
+		return nil, fmt.Errorf("empty pod selector")
+	}
+	return s, nil
+}
+
+// startAggregatedLogs shows the logs of the pods matching selector in LogsView, replacing the
+// logs shown before
+func (a *App) startAggregatedLogs(source, namespace string, selector labels.Selector) error {
+	if a.KubeClient == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	a.stopLogStream()
+
+	g := &logAggregator{
+		app:       a,
+		client:    a.KubeClient,
+		source:    source,
+		namespace: namespace,
+		selector:  selector,
+		options:   a.logOptions,
+		buffer:    &logBuffer{},
+		stopCh:    make(chan struct{}),
+		streams:   make(map[string]*aggregatedStream),
+		ended:     make(map[string]int32),
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(a.KubeClient, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector.String()
+		}))
+	informer := factory.Core().V1().Pods().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { g.podChanged(obj) },
+		UpdateFunc: func(_, obj interface{}) { g.podChanged(obj) },
+		DeleteFunc: func(obj interface{}) { g.podDeleted(obj) },
+	})
+	factory.Start(g.stopCh)
+	go func() {
+		if cache.WaitForCacheSync(g.stopCh, informer.HasSynced) {
+			g.synced.Store(true)
+		}
+	}()
+
+	a.aggregator = g
+	a.logBuffer = g.buffer
+	a.logView.paused, a.logView.pausedLines = false, nil
+	a.logSource = source
+	a.LogsView.SetText("[yellow]Waiting for pods...")
+	a.updateLogsTitle()
+	a.showLogsWindow(true)
+	return nil
+}
+
+// podChanged opens a stream for every container of a pod that has logs and is not streamed yet
+func (g *logAggregator) podChanged(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	restarts := make(map[string]int32)
+	started := make(map[string]bool)
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts[status.Name] = status.RestartCount
+		started[status.Name] = status.State.Running != nil || status.State.Terminated != nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.stopCh:
+		return
+	default:
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if !started[container.Name] {
+			continue
+		}
+		key := pod.Name + "/" + container.Name
+		if s, ok := g.streams[key]; ok && s.restarts == restarts[container.Name] {
+			continue
+		} else if ok {
+			s.cancel()
+		}
+		// The log of a container whose stream ended was read until then; only a restart has more
+		if count, ok := g.ended[key]; ok && count == restarts[container.Name] {
+			continue
+		}
+
+		// The logs of pods that were already running start with the chosen tail; the logs of
+		// new pods and restarted containers are read from their start
+		opts := g.options.podLogOptions(container.Name)
+		if g.synced.Load() {
+			opts.TailLines, opts.SinceSeconds = nil, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s := &aggregatedStream{cancel: cancel, restarts: restarts[container.Name]}
+		g.streams[key] = s
+		go g.stream(ctx, s, pod.Name, key, opts)
+	}
+	g.notify()
+}
+
+// podDeleted cancels the streams of a deleted pod
+func (g *logAggregator) podDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key, s := range g.streams {
+		if strings.HasPrefix(key, pod.Name+"/") {
+			s.cancel()
+			delete(g.streams, key)
+		}
+	}
+	for key := range g.ended {
+		if strings.HasPrefix(key, pod.Name+"/") {
+			delete(g.ended, key)
+		}
+	}
+	g.notify()
+}
+
+// stream copies the log lines of a container into the buffer, tagged with key, until s is
+// cancelled or the stream ends
+func (g *logAggregator) stream(ctx context.Context, s *aggregatedStream, podName, key string, opts *corev1.PodLogOptions) {
+	defer g.streamEnded(s, key)
+	color := logSourceColor(key)
+	stream, err := g.client.CoreV1().Pods(g.namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			g.buffer.add(logLine{source: key, color: color, text: fmt.Sprintf("error opening log stream: %v", err)})
+			g.notify()
+		}
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		g.buffer.add(logLine{source: key, color: color, text: strings.TrimSuffix(scanner.Text(), "\r")})
+		g.notify()
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		g.buffer.add(logLine{source: key, color: color, text: fmt.Sprintf("error reading log stream: %v", err)})
+		g.notify()
+	}
+}
+
+// streamEnded forgets the stream s of key once it ended, unless it was replaced
+func (g *logAggregator) streamEnded(s *aggregatedStream, key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.streams[key] != s {
+		return
+	}
+	delete(g.streams, key)
+	g.ended[key] = s.restarts
+	g.notify()
+}
+
+// notify queues a redraw of the log pane, so that pod events and streams are not held up by
+// a busy UI
+func (g *logAggregator) notify() {
+	g.app.queueLogsReceived(g.buffer)
+}
+
+// streamCount returns the number of open streams
+func (g *logAggregator) streamCount() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.streams)
+}
+
+// stop cancels every stream and stops watching pods
+func (g *logAggregator) stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	close(g.stopCh)
+	for key, s := range g.streams {
+		s.cancel()
+		delete(g.streams, key)
+	}
+}
+
+// logSourceColor picks the color of a pod/container tag, the same one every time
+func logSourceColor(source string) string {
+	h := fnv.New32a()
+	h.Write([]byte(source))
+	return logSourceColors[h.Sum32()%uint32(len(logSourceColors))]
+}
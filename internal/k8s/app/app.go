@@ -7,21 +7,39 @@ import (
 	"github.com/rivo/tview"
 )
 
-// NewApp creates a new instance of the application
+// NewApp creates a new instance of the application with default options
 func NewApp() *App {
+	return NewAppWithOptions(Options{})
+}
+
+// NewAppWithOptions creates a new instance of the application
+func NewAppWithOptions(opts Options) *App {
 	a := &App{
 		App:              tview.NewApplication(),
 		pages:            tview.NewPages(),
 		NsList:           tview.NewList(),
 
-		ResourceList:     tview.NewList(),
+		ResourceList:     NewResourceTable(),
 		ResourceTypeList: tview.NewList(),
 		InfoView:         tview.NewTextView().SetDynamicColors(true),
 		LogsView:         tview.NewTextView().SetDynamicColors(true),
+		StatusBar:        tview.NewTextView().SetDynamicColors(true),
 		CurrentFocus:     0,
 		stopChan:         make(chan struct{}),
 		logStopChan:      make(chan struct{}),
+		logOptions:       defaultLogOptions(),
+		ReadOnly:         opts.ReadOnly,
+		options:          opts,
+	}
+
+	// A broken config file is reported but does not prevent starting
+	config, err := LoadConfig(opts.ConfigFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
+	a.config = config
+	a.logDir = firstNonEmpty(opts.LogDir, config.LogDir, defaultLogDir())
+	a.logOptions.gzip = opts.GzipLogs || config.GzipLogs
 
 	// Initialize the UI
 	a.initUI()
@@ -40,6 +58,18 @@ func (a *App) Run() error {
 	if err := a.LoadNamespaces(); err != nil {
 		return fmt.Errorf("error loading namespaces: %v", err)
 	}
+	a.updateTitle()
+
+	// Custom resources are optional, so a failed discovery only limits the resource types shown
+	_ = a.discoverResourceKinds()
+
+	// Open the namespace and resource type requested on the command line
+	if a.options.Resource != "" {
+		a.SelectedResourceType = a.options.Resource
+	}
+	if err := a.openCurrentView(); err != nil {
+		return err
+	}
 
 	// Start the application
 	if err := a.App.Run(); err != nil {
@@ -47,12 +77,33 @@ func (a *App) Run() error {
 	}
 
 	// Clean up
-	if a.logStream != nil {
-		a.logStream.Close()
+	a.stopWatches()
+	a.stopLogStream()
+	a.stopPortForwards()
+
+	return nil
+}
+
+// openCurrentView loads the resources of CurrentNs and SelectedResourceType into the resource list
+func (a *App) openCurrentView() error {
+	if a.CurrentNs == "" && !a.allNamespaces && a.SelectedResourceType == "" {
+		return nil
+	}
+
+	resourceType := a.SelectedResourceType
+	kind, ok := a.lookupKind(resourceType)
+	if !ok {
+		resourceType = ResourceTypePod
+		kind, _ = lookupResourceKind(resourceType)
+	}
+	if a.CurrentNs == "" && !a.allNamespaces && kind.Namespaced {
+		return nil
 	}
-	if a.logStopChan != nil {
-		close(a.logStopChan)
+	if err := a.LoadResources(resourceType); err != nil {
+		return fmt.Errorf("error loading %s: %v", GetResourceDisplayName(resourceType), err)
 	}
 
+	a.CurrentFocus = 2
+	a.UpdateFocus()
 	return nil
 }
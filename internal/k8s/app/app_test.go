@@ -1,13 +1,43 @@
 package app
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/portforward"
+	utilexec "k8s.io/utils/exec"
 
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -45,14 +75,17 @@ func TestLoadNamespaces(t *testing.T) {
 	app.KubeClient = fakeClient
 	
 	// Test loading namespaces
-	err := app.LoadNamespaces()
+	err := load(t, app, app.LoadNamespaces)
 	require.NoError(t, err, "LoadNamespaces should not return an error")
 	
-	// Verify namespaces were loaded
-	assert.Equal(t, 3, app.NsList.GetItemCount(), "Should load 3 namespaces")
+	// Verify namespaces were loaded, after the all namespaces entry
+	assert.Equal(t, 4, app.NsList.GetItemCount(), "Should list the All namespaces entry and 3 namespaces")
 	
-	// Test that the first namespace is "default"
 	name, _ := app.NsList.GetItemText(0)
+	assert.Contains(t, name, allNamespacesItem, "The first entry should list every namespace")
+
+	// Test that the first namespace is "default"
+	name, _ = app.NsList.GetItemText(1)
 	assert.Equal(t, "default", name, "First namespace should be 'default'")
 }
 
@@ -82,18 +115,15 @@ func TestLoadPods(t *testing.T) {
 	app.KubeClient = fakeClient
 	
 	// Load pods into the UI
-	err := app.LoadPods()
+	err := load(t, app, app.LoadPods)
 	require.NoError(t, err, "LoadPods should not return an error")
 	
 	// Verify pods were loaded
-	assert.Equal(t, 2, app.ResourceList.GetItemCount(), "Should load 2 pods")
+	assert.Equal(t, 2, app.ResourceList.ItemCount(), "Should load 2 pods")
 	
 	// Test pod names
-	name1, _ := app.ResourceList.GetItemText(0)
-	assert.Equal(t, "test-pod-1", name1, "First pod should be 'test-pod-1'")
-	
-	name2, _ := app.ResourceList.GetItemText(1)
-	assert.Equal(t, "test-pod-2", name2, "Second pod should be 'test-pod-2'")
+	assert.Equal(t, "test-pod-1", app.ResourceList.Item(0)[0], "First pod should be 'test-pod-1'")
+	assert.Equal(t, "test-pod-2", app.ResourceList.Item(1)[0], "Second pod should be 'test-pod-2'")
 }
 
 // TestLoadContainers tests loading containers for a pod
@@ -131,16 +161,11 @@ func TestLoadContainers(t *testing.T) {
 	require.NoError(t, err, "LoadContainers should not return an error")
 	
 	// Verify containers were loaded
-	assert.Equal(t, 2, app.ResourceList.GetItemCount(), "Should load 2 containers")
+	assert.Equal(t, 2, app.ResourceList.ItemCount(), "Should load 2 containers")
 	
 	// Test container names
-	name1, desc1 := app.ResourceList.GetItemText(0)
-	assert.Equal(t, "nginx", name1, "First container should be 'nginx'")
-	assert.Equal(t, "", desc1, "Container description should be empty")
-	
-	name2, desc2 := app.ResourceList.GetItemText(1)
-	assert.Equal(t, "redis", name2, "Second container should be 'redis'")
-	assert.Equal(t, "", desc2, "Container description should be empty")
+	assert.Equal(t, []string{"nginx", "nginx:latest", "true", "<unknown>", "0"}, app.ResourceList.Item(0), "First container should be 'nginx'")
+	assert.Equal(t, []string{"redis", "redis:alpine", "false", "<unknown>", "0"}, app.ResourceList.Item(1), "Second container should be 'redis'")
 }
 
 // TestLoadPodsWithNoNamespace tests error handling when no namespace is selected
@@ -150,7 +175,7 @@ func TestLoadPodsWithNoNamespace(t *testing.T) {
 	// Test with no namespace selected
 	app.CurrentNs = ""
 	
-	err := app.LoadPods()
+	err := load(t, app, app.LoadPods)
 	assert.Error(t, err, "LoadPods should return an error when no namespace is selected")
 	assert.Contains(t, err.Error(), "no namespace selected")
 }
@@ -178,7 +203,7 @@ func TestAppInitializationWithKubeClient(t *testing.T) {
 	app.KubeClient = fakeClient
 	
 	// Test that we can load namespaces
-	err := app.LoadNamespaces()
+	err := load(t, app, app.LoadNamespaces)
 	require.NoError(t, err, "LoadNamespaces should work with mocked client")
 	
 	// Test that we can set namespace and load pods
@@ -192,9 +217,9 @@ func TestAppInitializationWithKubeClient(t *testing.T) {
 		},
 	}, metav1.CreateOptions{})
 	
-	err = app.LoadPods()
+	err = load(t, app, app.LoadPods)
 	require.NoError(t, err, "LoadPods should work with mocked client")
-	assert.Equal(t, 1, app.ResourceList.GetItemCount(), "Should load 1 pod")
+	assert.Equal(t, 1, app.ResourceList.ItemCount(), "Should load 1 pod")
 }
 
 // TestAppMethodsWithRealDependencies tests that app methods work with real dependencies
@@ -215,8 +240,8 @@ func TestAppMethodsWithRealDependencies(t *testing.T) {
 	
 	// Test that we can call methods without panicking
 	// These will return errors due to no client, but should not panic
-	_ = app.LoadNamespaces()
-	_ = app.LoadPods()
+	_ = load(t, app, app.LoadNamespaces)
+	_ = load(t, app, app.LoadPods)
 	_ = app.LoadContainers("test")
 }
 
@@ -277,22 +302,1692 @@ func TestIntegrationWithMockClient(t *testing.T) {
 	// Test complete workflow
 	
 	// 1. Load namespaces
-	err := app.LoadNamespaces()
+	err := load(t, app, app.LoadNamespaces)
 	require.NoError(t, err)
-	assert.Equal(t, 1, app.NsList.GetItemCount(), "Should load 1 namespace")
+	assert.Equal(t, 2, app.NsList.GetItemCount(), "Should load 1 namespace and the all namespaces entry")
 	
 	// 2. Select namespace and load pods
 	app.CurrentNs = "default"
-	err = app.LoadPods()
+	err = load(t, app, app.LoadPods)
 	require.NoError(t, err)
-	assert.Equal(t, 1, app.ResourceList.GetItemCount(), "Should load 1 pod")
+	assert.Equal(t, 1, app.ResourceList.ItemCount(), "Should load 1 pod")
 	
 	// 3. Select pod and load containers
 	err = app.LoadContainers("test-pod-1")
 	require.NoError(t, err)
-	assert.Equal(t, 2, app.ResourceList.GetItemCount(), "Should load 2 containers")
+	assert.Equal(t, 2, app.ResourceList.ItemCount(), "Should load 2 containers")
 	
 	// 4. Verify the integration works
 	assert.Equal(t, "default", app.CurrentNs, "Current namespace should be 'default'")
 	// Note: SelectedPod is set via UI callback, not directly in LoadPods
 }
+
+// runtimeNotFound is how runc reports an exec of a missing command
+var runtimeNotFound = errors.New(`Internal error occurred: error executing command in container: failed to exec in container: ` +
+	`OCI runtime exec failed: exec failed: unable to start container process: exec: "bash": executable file not found in $PATH: unknown`)
+
+// TestIsCommandNotFound tests detection of missing shells in exec errors
+func TestIsCommandNotFound(t *testing.T) {
+	assert.True(t, isCommandNotFound(runtimeNotFound))
+	assert.True(t, isCommandNotFound(utilexec.CodeExitError{Err: errors.New(`exec: "ash": stat /bin/ash: no such file or directory`), Code: 126}))
+	assert.False(t, isCommandNotFound(utilexec.CodeExitError{Err: errors.New("command terminated with non-zero exit code: exit code 127"), Code: 127}),
+		"A shell that started and exited with 127 was found")
+	assert.False(t, isCommandNotFound(utilexec.CodeExitError{Err: errors.New("exit"), Code: 1}))
+	assert.False(t, isCommandNotFound(errors.New("connection refused")))
+	assert.False(t, isCommandNotFound(nil))
+}
+
+// TestExecShellFallback tests that shells are tried in turn only while they fail to start
+func TestExecShellFallback(t *testing.T) {
+	app := NewApp()
+	var tried []string
+	results := map[string]error{}
+	app.exec = func(containerName string, command []string) error {
+		tried = append(tried, command[0])
+		return results[command[0]]
+	}
+
+	// The user typed a missing command in bash, then exit: the session is over
+	results["bash"] = utilexec.CodeExitError{Err: errors.New("command terminated with non-zero exit code: exit code 127"), Code: 127}
+	err := app.ExecShell("app")
+	assert.Equal(t, results["bash"], err)
+	assert.Equal(t, []string{"bash"}, tried, "No other shell should be opened once bash ran")
+
+	// The image has no bash
+	tried = nil
+	results["bash"] = runtimeNotFound
+	assert.NoError(t, app.ExecShell("app"))
+	assert.Equal(t, []string{"bash", "sh"}, tried)
+}
+
+// TestParseResourceType tests resolving resource types from command-line names
+func TestParseResourceType(t *testing.T) {
+	for name, expected := range map[string]ResourceType{
+		"pod":                    ResourceTypePod,
+		"pods":                   ResourceTypePod,
+		"Deployments":            ResourceTypeDeployment,
+		"persistentvolumeclaims": ResourceTypePVC,
+		"nodes":                  ResourceTypeNode,
+	} {
+		resourceType, err := ParseResourceType(name)
+		require.NoError(t, err, "ParseResourceType(%q) should not return an error", name)
+		assert.Equal(t, expected, resourceType)
+	}
+
+	_, err := ParseResourceType("widgets")
+	assert.Error(t, err, "ParseResourceType should reject unknown types")
+}
+
+// TestNewAppWithOptions tests that startup options select the namespace
+func TestNewAppWithOptions(t *testing.T) {
+	app := NewAppWithOptions(Options{Namespace: "kube-system", ReadOnly: true})
+
+	assert.Equal(t, "kube-system", app.CurrentNs, "Startup namespace should be selected")
+	assert.True(t, app.ReadOnly, "Read-only option should be applied")
+}
+
+// TestSwitchContextRestoresState tests that every context remembers its last view
+func TestSwitchContextRestoresState(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster: {server: "http://127.0.0.1:1"}
+- name: prod
+  cluster: {server: "http://127.0.0.1:1"}
+users:
+- name: admin
+  user: {}
+contexts:
+- name: staging
+  context: {cluster: staging, user: admin}
+- name: prod
+  context: {cluster: prod, user: admin, namespace: web}
+`), 0o600))
+
+	app := NewAppWithOptions(Options{Kubeconfig: kubeconfig})
+	require.Equal(t, "staging", app.CurrentContext, "Current context should come from the kubeconfig")
+
+	app.CurrentNs = "api"
+	app.SelectedResourceType = ResourceTypeDeployment
+
+	// The clusters are unreachable, so the lists never load, but the state must still switch
+	_ = app.switchContext("prod")
+	assert.Equal(t, "prod", app.CurrentContext)
+	assert.Equal(t, "web", app.CurrentNs, "First visit should use the context namespace")
+	assert.Equal(t, ResourceTypePod, app.SelectedResourceType, "First visit should list pods")
+
+	_ = app.switchContext("staging")
+	assert.Equal(t, "staging", app.CurrentContext)
+	assert.Equal(t, "api", app.CurrentNs, "Namespace should be restored")
+	assert.Equal(t, ResourceTypeDeployment, app.SelectedResourceType, "Resource type should be restored")
+}
+
+// load runs f, which loads views of app, then runs the UI updates queued by their watches on
+// the test goroutine, as the event loop would, until the views are rendered
+func load(t *testing.T, app *App, f func() error) error {
+	t.Helper()
+	if app.updates == nil {
+		app.updates = make(chan func(), 100)
+	}
+	if err := f(); err != nil {
+		return err
+	}
+	loading := func(w *resourceWatch) bool { return w != nil && !w.loaded && !w.stopped() }
+	timeout := time.After(5 * time.Second)
+	for loading(app.resourceWatch) || loading(app.namespaceWatch) {
+		select {
+		case update := <-app.updates:
+			update()
+		case <-timeout:
+			t.Fatal("Timed out loading")
+		}
+	}
+	return nil
+}
+
+// pressKey delivers a key as tview does: through the global input capture, then to the
+// focused primitive
+func pressKey(app *App, event *tcell.EventKey) {
+	if event = app.App.GetInputCapture()(event); event != nil {
+		app.App.GetFocus().InputHandler()(event, func(p tview.Primitive) { app.App.SetFocus(p) })
+	}
+}
+
+// TestContextPickerKeys tests that the letters typed in the context picker are its own, so
+// that q closes it instead of quitting, and that the context shows in the title
+func TestContextPickerKeys(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster: {server: "http://127.0.0.1:1"}
+users:
+- name: admin
+  user: {}
+contexts:
+- name: staging
+  context: {cluster: staging, user: admin}
+- name: team[prod]
+  context: {cluster: staging, user: admin}
+`), 0o600))
+
+	app := NewAppWithOptions(Options{Kubeconfig: kubeconfig})
+	app.updateTitle()
+	assert.Contains(t, app.grid.GetTitle(), "[staging[]", "The context should not be read as a color tag")
+
+	app.showContextPicker()
+	require.True(t, app.pages.HasPage("contexts"))
+	picker := app.App.GetFocus().(*tview.List)
+	current, _ := picker.GetItemText(0)
+	other, _ := picker.GetItemText(1)
+	assert.Equal(t, "[green]* staging", current)
+	assert.Equal(t, "team[prod[]", other, "Context names should not be read as color tags")
+	pressKey(app, tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone))
+	pressKey(app, tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone))
+	assert.False(t, app.pages.HasPage("contexts"), "q should close the picker")
+	front, _ := app.pages.GetFrontPage()
+	assert.Equal(t, "main", front)
+}
+
+// TestWatchPicksUpNewPods tests that the pod list informer sees pods created after loading
+func TestWatchPicksUpNewPods(t *testing.T) {
+	app := NewApp()
+	app.CurrentNs = "default"
+
+	fakeClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod-1", Namespace: "default"},
+	})
+	app.KubeClient = fakeClient
+
+	require.NoError(t, load(t, app, app.LoadPods))
+	defer app.stopWatches()
+	assert.Equal(t, 1, app.ResourceList.ItemCount(), "Should load 1 pod")
+
+	_, err := fakeClient.CoreV1().Pods("default").Create(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod-2", Namespace: "default"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(app.resourceWatch.objects()) == 2
+	}, 5*time.Second, 10*time.Millisecond, "Watch should pick up the new pod")
+
+	// Leaving the pod list stops its informer
+	require.NoError(t, app.LoadContainers("test-pod-1"))
+	assert.Nil(t, app.resourceWatch, "Pod watch should be stopped when showing containers")
+}
+
+// TestWatchLoadsInBackground tests that loading a view does not wait for a slow API server,
+// showing the view as loading, and that a failed list is shown once it fails
+func TestWatchLoadsInBackground(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	app := NewApp()
+	app.CurrentNs = "default"
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	app.KubeClient = clientset
+	app.updates = make(chan func(), 100)
+	defer app.stopWatches()
+
+	require.NoError(t, app.LoadPods())
+	assert.Contains(t, app.StatusBar.GetText(true), "Loading pods...")
+	assert.Equal(t, 0, app.ResourceList.ItemCount())
+	assert.False(t, app.pages.HasPage("error"))
+
+	close(release)
+	select {
+	case update := <-app.updates:
+		update()
+	case <-time.After(5 * time.Second):
+		t.Fatal("The failed list should be reported")
+	}
+	assert.True(t, app.pages.HasPage("error"), "The failed list should be shown")
+	assert.True(t, app.resourceWatch.stopped())
+	assert.NotContains(t, app.StatusBar.GetText(true), "Loading")
+}
+
+// TestLoadResourcesForEveryRegisteredType tests that every registered kind can be listed
+func TestLoadResourcesForEveryRegisteredType(t *testing.T) {
+	app := NewApp()
+	app.CurrentNs = "default"
+	app.KubeClient = fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: func(i int32) *int32 { return &i }(3)},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+		},
+	)
+	defer app.stopWatches()
+
+	for _, resourceType := range GetAllResourceTypes() {
+		require.NoError(t, load(t, app, func() error { return app.LoadResources(resourceType) }), "LoadResources(%s) should not return an error", resourceType)
+		assert.Equal(t, resourceType, app.SelectedResourceType)
+	}
+
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeDeployment) }))
+	require.Equal(t, 1, app.ResourceList.ItemCount(), "Should load 1 deployment")
+	row := app.ResourceList.Item(0)
+	assert.Equal(t, "web", row[0])
+	assert.Equal(t, "2/3", row[1], "Deployment should show ready replicas")
+
+	assert.Error(t, load(t, app, func() error { return app.LoadResources("widget") }), "Unknown resource types should be rejected")
+}
+
+// TestDiscoverCustomResources tests that custom resources are discovered and listed with their printer columns
+func TestDiscoverCustomResources(t *testing.T) {
+	app := NewApp()
+	app.CurrentNs = "default"
+
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"list", "watch"}}},
+		},
+		{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "certificates", Kind: "Certificate", Namespaced: true, Verbs: []string{"get", "list", "watch"}},
+				{Name: "certificates/status", Kind: "Certificate", Namespaced: true, Verbs: []string{"get"}},
+			},
+		},
+	}
+	app.KubeClient = fakeClient
+
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "certificates.cert-manager.io"},
+		"spec": map[string]interface{}{
+			"group": "cert-manager.io",
+			"names": map[string]interface{}{"plural": "certificates"},
+			"versions": []interface{}{map[string]interface{}{
+				"name": "v1",
+				"additionalPrinterColumns": []interface{}{
+					map[string]interface{}{"name": "Ready", "type": "string", "jsonPath": `.status.conditions[?(@.type=="Ready")].status`},
+					map[string]interface{}{"name": "Secret", "type": "string", "jsonPath": ".spec.secretName"},
+					map[string]interface{}{"name": "Issuer", "type": "string", "jsonPath": ".spec.issuerRef.name", "priority": int64(1)},
+				},
+			}},
+		},
+	}}
+	cert := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"name": "web-tls", "namespace": "default"},
+		"spec":       map[string]interface{}{"secretName": "web-tls-secret", "issuerRef": map[string]interface{}{"name": "letsencrypt"}},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}},
+	}}
+	app.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		crdGVR:       "CustomResourceDefinitionList",
+		certificates: "CertificateList",
+	}, crd, cert)
+	defer app.stopWatches()
+
+	require.NoError(t, app.discoverResourceKinds())
+	require.Len(t, app.discoveredKinds, 1, "Only unregistered, listable resources should be discovered")
+
+	kind := app.discoveredKinds[0]
+	assert.Equal(t, ResourceType("certificates.cert-manager.io"), kind.Type)
+	assert.Equal(t, []string{"READY", "SECRET", "AGE"}, kind.Columns)
+
+	header, _ := app.ResourceTypeList.GetItemText(len(resourceKinds))
+	assert.Contains(t, header, "cert-manager.io", "Discovered kinds should be grouped under their API group")
+
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(kind.Type) }))
+	require.Equal(t, 1, app.ResourceList.ItemCount(), "Should list 1 certificate")
+	assert.Equal(t, []string{"web-tls", "True", "web-tls-secret"}, app.ResourceList.Item(0)[:3], "Certificate should show printer columns")
+
+	objects := app.resourceWatch.objects()
+	require.Len(t, objects, 1)
+	app.selectResource(kind, objects[0])
+	assert.Contains(t, app.InfoView.GetText(true), "letsencrypt", "Detail view should include the spec")
+}
+
+// TestResourceTableSorting tests sorting the resource table on a column in both directions
+func TestResourceTableSorting(t *testing.T) {
+	table := NewResourceTable()
+	table.SetColumns([]string{"NAME", "RESTARTS", "AGE"})
+	table.AddRow("a", []string{"a", "10", "2d"}, nil, nil)
+	table.AddRow("b", []string{"b", "9", "5h"}, nil, nil)
+	table.AddRow("c", []string{"c", "0 (3m ago)", "1mo"}, nil, nil)
+	table.Render()
+
+	names := func() []string {
+		var names []string
+		for i := 0; i < table.ItemCount(); i++ {
+			names = append(names, table.Item(i)[0])
+		}
+		return names
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names(), "Rows should be sorted by name")
+	table.Select(3, 0)
+
+	table.SortBy(1)
+	assert.Equal(t, []string{"c", "b", "a"}, names(), "Restarts should sort numerically")
+	assert.Equal(t, "c", table.SelectedKey(), "Selection should follow the row")
+
+	table.SortBy(1)
+	assert.Equal(t, []string{"a", "b", "c"}, names(), "Sorting twice should reverse the order")
+
+	table.SortBy(2)
+	assert.Equal(t, []string{"b", "a", "c"}, names(), "Ages should sort by duration")
+	assert.Equal(t, "AGE↑", table.GetCell(0, 2).Text)
+}
+
+func TestCellNumber(t *testing.T) {
+	tests := []struct {
+		cell string
+		want float64
+		ok   bool
+	}{
+		{"3", 3, true},
+		{"0 (3m ago)", 0, true},
+		{"2/3", 2, true},
+		{"5d", 5 * 86400, true},
+		{"1mo", 30 * 86400, true},
+		{"5d3h", 5*86400 + 3*3600, true},
+		{"2y10d", 2*365*86400 + 10*86400, true},
+		{"3m20s", 200, true},
+		{"1mo2d", 32 * 86400, true},
+		{"web", 0, false},
+		{"5x", 0, false},
+		{"3m20", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cell, func(t *testing.T) {
+			got, ok := cellNumber(tt.cell)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Equal(t, -1, compareCells("3m20s", "5m"), "Multi-unit ages should sort by duration")
+	assert.Equal(t, 1, compareCells("2y10d", "400d"))
+}
+
+// TestServerTablePrinting tests that resource columns come from the server-printed table, listed
+// once and then watched, falling back to the built-in columns when the server answers plain lists
+func TestServerTablePrinting(t *testing.T) {
+	table := `{"kind":"Table","apiVersion":"meta.k8s.io/v1",
+		"columnDefinitions":[
+			{"name":"Name","type":"string","priority":0},
+			{"name":"Ready","type":"string","priority":0},
+			{"name":"Restarts","type":"integer","priority":0},
+			{"name":"Node","type":"string","priority":1}],
+		"rows":[{"cells":["web-1","1/1",2,"node-a"],
+			"object":{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1","metadata":{"name":"web-1","namespace":"default"}}}]}`
+	changes := `{"type":"ADDED","object":{"kind":"Table","apiVersion":"meta.k8s.io/v1",
+			"rows":[{"cells":["web-2","0/1",0,"node-b"],
+				"object":{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1","metadata":{"name":"web-2","namespace":"default"}}}]}}
+		{"type":"DELETED","object":{"kind":"Table","apiVersion":"meta.k8s.io/v1",
+			"rows":[{"cells":["web-1","1/1",2,"node-a"],
+				"object":{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1","metadata":{"name":"web-1","namespace":"default"}}}]}}`
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/default/pods" && strings.Contains(r.Header.Get("Accept"), "as=Table") {
+			if r.URL.Query().Get("watch") != "true" {
+				lists.Add(1)
+				w.Write([]byte(table))
+				return
+			}
+			w.Write([]byte(changes))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"kind":"ConfigMapList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	app := NewApp()
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	app.KubeClient = clientset
+	defer app.stopTableWatch()
+
+	pods, _ := lookupResourceKind(ResourceTypePod)
+	watch := app.startTableWatch(pods, "default")
+	require.NotNil(t, watch, "The server should be asked to print pods as a table")
+	assert.Same(t, watch, app.startTableWatch(pods, "default"), "Reloading the same list should reuse the watch")
+
+	rowNames := func() []string {
+		var names []string
+		if result := watch.snapshot(); result != nil {
+			for _, row := range result.Rows {
+				names = append(names, row.Cells[0].(string))
+			}
+		}
+		return names
+	}
+	assert.Eventually(t, func() bool { return reflect.DeepEqual(rowNames(), []string{"web-2"}) },
+		5*time.Second, 10*time.Millisecond, "Watched changes should update the table")
+	assert.Equal(t, int32(1), lists.Load(), "The table should be listed once, then watched")
+
+	result := watch.snapshot()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default"}}
+	app.fillServerTable(pods, result, []runtime.Object{pod})
+	assert.Equal(t, []string{"web-2", "0/1", "0"}, app.ResourceList.Item(0), "Only default columns should be shown")
+
+	app.wideColumns = true
+	app.fillServerTable(pods, result, []runtime.Object{pod})
+	assert.Equal(t, []string{"web-2", "0/1", "0", "node-b"}, app.ResourceList.Item(0), "Wide columns should be shown")
+
+	configMaps, _ := lookupResourceKind(ResourceTypeConfigMap)
+	watch = app.startTableWatch(configMaps, "default")
+	require.NotNil(t, watch)
+	assert.Eventually(t, func() bool { return !app.serverTableSupported(configMaps.GVR) },
+		5*time.Second, 10*time.Millisecond, "Unsupported resources should not be requested again")
+	assert.Nil(t, watch.snapshot(), "Plain lists should fall back to the built-in columns")
+}
+
+// TestFilterResources tests fuzzy, label and status filters on the resource table
+func TestFilterResources(t *testing.T) {
+	app := NewApp()
+	app.CurrentNs = "default"
+	crashing := corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}}
+	app.KubeClient = fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx-abc", Namespace: "default", Labels: map[string]string{"app": "nginx"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "redis-0", Namespace: "default", Labels: map[string]string{"app": "redis"}}, Status: crashing},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"}},
+	)
+	require.NoError(t, load(t, app, app.LoadPods))
+	defer app.stopWatches()
+
+	filterBy := func(text string) []string {
+		f, err := parseFilter(text)
+		require.NoError(t, err)
+		app.setResourceFilter(f)
+		var names []string
+		for i := 0; i < app.ResourceList.ItemCount(); i++ {
+			names = append(names, app.ResourceList.Item(i)[0])
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"redis-0", "worker"}, filterBy("e"), "Names should match fuzzily")
+	assert.Equal(t, []string{"nginx-abc"}, filterBy("ngx"), "Names should match fuzzily")
+	assert.Contains(t, app.ResourceList.GetCell(1, 0).Text, "[orange::b]x[-::-]", "Matched characters should be highlighted")
+	assert.Equal(t, []string{"nginx-abc"}, filterBy("l:app=nginx"))
+	assert.Equal(t, []string{"redis-0"}, filterBy("s:crashloop"))
+	assert.Equal(t, []string{"nginx-abc", "redis-0"}, filterBy("l:app"))
+	assert.Nil(t, filterBy("zzz"))
+
+	_, err := parseFilter("l:=nginx")
+	assert.Error(t, err, "Invalid label selectors should be rejected")
+
+	// The filter stays applied when the list is re-rendered
+	filterBy("l:app=nginx")
+	require.NoError(t, load(t, app, app.LoadPods))
+	assert.Equal(t, 1, app.ResourceList.ItemCount(), "Filter should persist across updates")
+	assert.Contains(t, app.ResourceList.GetTitle(), "l:app=nginx")
+
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeDeployment) }))
+	require.NoError(t, load(t, app, app.LoadPods))
+	assert.Equal(t, 3, app.ResourceList.ItemCount(), "Switching resource types should clear the filter")
+}
+
+// TestManifestView tests rendering, searching and folding the manifest of a resource
+func TestManifestView(t *testing.T) {
+	app := NewApp()
+	app.CurrentNs = "default"
+	app.KubeClient = fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "web",
+			Namespace:     "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+	})
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeDeployment) }))
+	defer app.stopWatches()
+
+	app.showManifest()
+	require.NotNil(t, app.manifest, "The manifest of the highlighted row should be shown")
+	text := app.InfoView.GetText(true)
+	assert.Contains(t, text, "kind: Deployment")
+	assert.Contains(t, text, "readyReplicas: 2")
+	assert.NotContains(t, text, "managedFields", "managedFields should be stripped by default")
+	assert.Contains(t, app.InfoView.GetText(false), manifestKeyColor+"kind", "Keys should be highlighted")
+
+	app.searchManifest("WEB")
+	assert.Equal(t, 1, app.manifest.matches, "Search should be case-insensitive")
+	assert.Equal(t, []string{"match-0"}, app.InfoView.GetHighlights())
+
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone))
+	assert.NotContains(t, app.InfoView.GetText(true), "readyReplicas", "Status should be folded")
+
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyRune, 'm', tcell.ModNone))
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone))
+	text = app.InfoView.GetText(true)
+	assert.Contains(t, text, `"kind": "Deployment"`, "JSON should be shown")
+	assert.Contains(t, text, `"managedFields"`, "managedFields should be shown when toggled")
+
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	assert.Nil(t, app.manifest)
+	assert.Equal(t, 2, app.CurrentFocus, "Closing the manifest should return to the resource table")
+}
+
+// TestHighlightManifestRegions tests that search matches are wrapped in numbered regions
+func TestHighlightManifestRegions(t *testing.T) {
+	text, matches := highlightManifest("name: web-web\nimage: [web]", false, "web")
+	assert.Equal(t, 3, matches)
+	assert.Contains(t, text, `["match-0"]web[""]-["match-1"]web`)
+
+	view := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetText(text)
+	assert.Equal(t, "name: web-web\nimage: [web]", view.GetText(true), "Highlighting should not change the text")
+}
+
+// TestEditResource tests editing an object, including reopening the editor after an error
+func TestEditResource(t *testing.T) {
+	app := NewApp()
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	app.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMaps: "ConfigMapList"},
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "settings", "namespace": "default", "resourceVersion": "1"},
+			"data":       map[string]interface{}{"mode": "slow"},
+		}})
+	kind, _ := lookupResourceKind(ResourceTypeConfigMap)
+
+	var buffers []string
+	app.editor = func(path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		buffers = append(buffers, string(data))
+
+		// The first save renames the object, which is refused; the second one fixes it
+		edited := strings.Replace(string(data), "mode: slow", "mode: fast", 1)
+		if len(buffers) == 1 {
+			edited = strings.Replace(edited, "name: settings", "name: renamed", 1)
+		} else {
+			edited = strings.Replace(edited, "name: renamed", "name: settings", 1)
+		}
+		return os.WriteFile(path, []byte(edited), 0600)
+	}
+
+	edited, err := app.editResource(kind, "default", "settings")
+	require.NoError(t, err)
+	assert.True(t, edited)
+	require.Len(t, buffers, 2, "The editor should be reopened after a failed save")
+	assert.True(t, strings.HasPrefix(buffers[0], editHeader), "The buffer should start with the edit header")
+	assert.Contains(t, buffers[1], "# error: metadata.name cannot be changed", "The error should be annotated at the top")
+
+	live, err := app.DynamicClient.Resource(configMaps).Namespace("default").Get(context.TODO(), "settings", metav1.GetOptions{})
+	require.NoError(t, err)
+	mode, _, _ := unstructured.NestedString(live.Object, "data", "mode")
+	assert.Equal(t, "fast", mode, "The edited object should be applied")
+
+	// Saving without changes leaves the object alone
+	app.editor = func(string) error { return nil }
+	edited, err = app.editResource(kind, "default", "settings")
+	require.NoError(t, err)
+	assert.False(t, edited)
+}
+
+// TestEditApplyConflict tests that manifests without a resourceVersion are applied without
+// taking over the fields of other managers, whose conflicts are shown in the editor
+func TestEditApplyConflict(t *testing.T) {
+	var patches []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPatch {
+			patches = append(patches, r)
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409,` +
+				`"message":"Apply failed with 1 conflict: conflict with \"hpa-controller\": .data.mode"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"default","resourceVersion":"7"},"data":{"mode":"slow"}}`))
+	}))
+	defer server.Close()
+
+	app := NewApp()
+	require.NoError(t, app.setKubeClient(&rest.Config{Host: server.URL}))
+	kind, _ := lookupResourceKind(ResourceTypeConfigMap)
+	var buffers []string
+	app.editor = func(path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		buffers = append(buffers, string(data))
+		if len(buffers) > 1 {
+			return os.WriteFile(path, nil, 0600)
+		}
+		edited := strings.Replace(string(data), "mode: slow", "mode: fast", 1)
+		edited = strings.Replace(edited, "resourceVersion: \"7\"\n", "", 1)
+		return os.WriteFile(path, []byte(edited), 0600)
+	}
+
+	edited, err := app.editResource(kind, "default", "settings")
+	require.NoError(t, err)
+	assert.False(t, edited)
+	require.Len(t, patches, 1)
+	assert.Equal(t, string(types.ApplyPatchType), patches[0].Header.Get("Content-Type"))
+	assert.Empty(t, patches[0].URL.Query().Get("force"), "The apply should not be forced")
+	require.Len(t, buffers, 2)
+	assert.Contains(t, buffers[1], `# error: Apply failed with 1 conflict: conflict with "hpa-controller": .data.mode`)
+	assert.Contains(t, buffers[1], "# error: Remove the conflicting fields")
+}
+
+func TestDescribePod(t *testing.T) {
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-1",
+			Namespace:       "default",
+			UID:             "pod-uid",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{
+			Containers:  []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+			Volumes:     []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}}},
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Value: "web", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady"}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "app",
+				RestartCount:         3,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}},
+		},
+	}
+	now := time.Now()
+	event := func(name, object string, uid types.UID, reason string, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: object, Namespace: "default", UID: uid},
+			Reason:         reason,
+			Type:           corev1.EventTypeWarning,
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	app := NewApp()
+	app.KubeClient = fake.NewSimpleClientset(pod,
+		event("e1", "web-1", "pod-uid", "BackOff", now.Add(-time.Minute)),
+		event("e2", "web-1", "pod-uid", "Pulled", now.Add(-time.Hour)),
+		event("e3", "web-1", "old-uid", "Scheduled", now),
+		event("e4", "web-2", "other-uid", "Killing", now),
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e5", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Service", Name: "web-1", Namespace: "default"},
+			Reason:         "SyncLoadBalancerFailed",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	)
+	kind, _ := lookupResourceKind(ResourceTypePod)
+
+	text, err := app.describeObject(kind, pod)
+	require.NoError(t, err)
+	for _, expected := range []string{"ReplicaSet", "web-abc (controller)", "ContainersNotReady", "CrashLoopBackOff",
+		"OOMKilled, exit code 137", "Restart Count: 3", "ConfigMap web-config", "dedicated=web:NoSchedule"} {
+		assert.Contains(t, text, expected)
+	}
+
+	events, err := app.objectEvents(pod)
+	require.NoError(t, err)
+	var reasons []string
+	for _, e := range events {
+		reasons = append(reasons, e.Reason)
+	}
+	assert.Equal(t, []string{"Pulled", "BackOff"}, reasons, "Only the pod's events should be shown, oldest first")
+	assert.NotContains(t, text, "SyncLoadBalancerFailed", "Events of other kinds with the same name should not be shown")
+}
+
+func TestEventsView(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+	now := time.Now()
+	event := func(name, kind, object, eventType, reason string, count int32, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: kind, Name: object, Namespace: "default"},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        reason + " happened",
+			Count:          count,
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	app := NewApp()
+	app.CurrentNs = "default"
+	app.KubeClient = fake.NewSimpleClientset(pod,
+		event("backoff-1", "Pod", "web-1", corev1.EventTypeWarning, "BackOff", 3, now.Add(-time.Hour)),
+		event("backoff-2", "Pod", "web-1", corev1.EventTypeWarning, "BackOff", 2, now.Add(-time.Minute)),
+		event("scheduled", "Pod", "web-1", corev1.EventTypeNormal, "Scheduled", 1, now.Add(-2*time.Hour)),
+		event("scaled", "Service", "web", corev1.EventTypeNormal, "Synced", 0, now),
+	)
+	app.DynamicClient = dynamicfake.NewSimpleDynamicClient(scheme.Scheme, pod)
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeEvent) }))
+	defer app.stopWatches()
+
+	rows := make(map[string][]string)
+	for i := 0; i < app.ResourceList.ItemCount(); i++ {
+		rows[app.ResourceList.Item(i)[3]] = app.ResourceList.Item(i)
+	}
+	require.Len(t, rows, 3, "Repeated events should be collapsed into one row")
+	assert.Equal(t, []string{"pod/web-1", "1m", "Warning", "BackOff", "5", "BackOff happened"}, rows["BackOff"])
+	assert.Equal(t, "1", rows["Synced"][4], "Events without a count occurred once")
+
+	for r := 1; r <= app.ResourceList.ItemCount(); r++ {
+		color, _, _ := app.ResourceList.GetCell(r, 0).Style.Decompose()
+		warning := app.ResourceList.GetCell(r, 2).Text == corev1.EventTypeWarning
+		assert.Equal(t, warning, color == eventWarningColor, "Only warnings should be colored")
+	}
+
+	f, err := parseFilter("pod/web s:back")
+	require.NoError(t, err)
+	app.setResourceFilter(f)
+	require.Equal(t, 1, app.ResourceList.ItemCount(), "Events should filter on the object and reason")
+
+	// Selecting the event opens the pod it is about
+	app.ResourceList.Select(1, 0)
+	app.ResourceList.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	assert.Equal(t, ResourceTypePod, app.SelectedResourceType)
+	assert.Equal(t, "web-1", app.SelectedPod)
+	assert.True(t, app.viewingContainers, "The pod's containers should be shown")
+}
+
+func TestAllNamespaces(t *testing.T) {
+	app := NewApp()
+	app.KubeClient = fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "cart:1"}}}},
+	)
+	require.NoError(t, load(t, app, app.LoadNamespaces))
+	defer app.stopWatches()
+
+	// Selecting the first entry lists the pods of every namespace
+	selectNamespace := func(item int) {
+		app.NsList.SetCurrentItem(item)
+		require.NoError(t, load(t, app, func() error {
+			app.NsList.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+			return nil
+		}))
+	}
+	selectNamespace(0)
+	assert.True(t, app.allNamespaces)
+	assert.Contains(t, app.ResourceList.GetCell(0, 1).Text, "NAMESPACE")
+	require.Equal(t, 2, app.ResourceList.ItemCount())
+
+	var cart int
+	for i := 0; i < app.ResourceList.ItemCount(); i++ {
+		if app.ResourceList.Item(i)[0] == "cart" {
+			cart = i
+		}
+	}
+	assert.Equal(t, "shop", app.ResourceList.Item(cart)[1])
+
+	// Actions on the pod use its own namespace
+	app.ResourceList.Select(cart+1, 0)
+	app.ResourceList.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	assert.Equal(t, "cart", app.SelectedPod)
+	assert.Equal(t, "shop", app.podNamespace())
+	assert.Equal(t, 1, app.ResourceList.ItemCount(), "The containers of the pod should be listed")
+
+	// Selecting a namespace lists only its pods again
+	selectNamespace(1)
+	assert.False(t, app.allNamespaces)
+	assert.Equal(t, "default", app.CurrentNs)
+	assert.Equal(t, 1, app.ResourceList.ItemCount())
+	assert.NotContains(t, app.ResourceList.GetCell(0, 1).Text, "NAMESPACE")
+}
+
+func TestLogOptions(t *testing.T) {
+	app := NewApp()
+	fakeClient := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+	app.KubeClient = fakeClient
+	app.CurrentNs = "default"
+	app.SelectedPod = "web"
+	defer app.stopLogStream()
+
+	logRequests := func() []*corev1.PodLogOptions {
+		var requests []*corev1.PodLogOptions
+		for _, action := range fakeClient.Actions() {
+			if action.GetSubresource() == "log" {
+				requests = append(requests, action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions))
+			}
+		}
+		return requests
+	}
+
+	require.NoError(t, app.ShowContainerLogs("app"))
+	require.Len(t, logRequests(), 1)
+	first := logRequests()[0]
+	assert.True(t, first.Follow, "Logs should be followed by default")
+	require.NotNil(t, first.TailLines)
+	assert.Equal(t, int64(100), *first.TailLines)
+	assert.Nil(t, first.SinceSeconds)
+
+	press := func(r rune) {
+		app.LogsView.InputHandler()(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), func(tview.Primitive) {})
+	}
+	for _, r := range "fptlls" {
+		press(r)
+	}
+	requests := logRequests()
+	require.Len(t, requests, 7, "Every option change should restart the stream")
+	last := requests[len(requests)-1]
+	assert.False(t, last.Follow)
+	assert.True(t, last.Previous)
+	assert.True(t, last.Timestamps)
+	assert.Nil(t, last.TailLines, "The whole log should be requested")
+	require.NotNil(t, last.SinceSeconds)
+	assert.Equal(t, int64(300), *last.SinceSeconds)
+	assert.Equal(t, "app", last.Container)
+
+	bar := app.logOptionsBar.GetText(true)
+	assert.Contains(t, bar, "follow:off")
+	assert.Contains(t, bar, "tail:all")
+	assert.Contains(t, bar, "since:5m")
+}
+
+func TestLogPaneControls(t *testing.T) {
+	app := NewApp()
+	app.SelectedCont = "app"
+	app.logBuffer = &logBuffer{}
+	app.logBuffer.write("GET /health 200\nGET /api 500\nPOST /api 2")
+	app.logBuffer.write("01\n")
+	app.renderLogs()
+	assert.Equal(t, "GET /health 200\nGET /api 500\nPOST /api 201", app.LogsView.GetText(true), "Chunks should be joined into lines")
+
+	// Search highlights every match, and n/N move between them
+	app.searchLogs("api")
+	assert.Equal(t, 2, app.logView.matches)
+	assert.Equal(t, []string{"match-0"}, app.LogsView.GetHighlights())
+	app.nextLogMatch(true)
+	assert.Equal(t, []string{"match-1"}, app.LogsView.GetHighlights())
+	app.nextLogMatch(false)
+	assert.Equal(t, []string{"match-0"}, app.LogsView.GetHighlights())
+	assert.Contains(t, app.LogsView.GetTitle(), "/api [1/2]")
+	app.searchLogs("")
+
+	// Filters apply to the lines already received and to new ones
+	require.NoError(t, app.setLogFilter(true, `^GET`))
+	require.NoError(t, app.setLogFilter(false, `health`))
+	assert.Equal(t, "GET /api 500", app.LogsView.GetText(true))
+	app.logBuffer.write("GET /users 200\nDELETE /users 204\n")
+	app.logsReceived()
+	assert.Equal(t, "GET /api 500\nGET /users 200", app.LogsView.GetText(true))
+	assert.Error(t, app.setLogFilter(true, `(`), "Invalid expressions should be rejected")
+	require.NoError(t, app.setLogFilter(true, ""))
+	require.NoError(t, app.setLogFilter(false, ""))
+
+	// Lines keep arriving while paused but are only shown on resume
+	app.togglePauseLogs()
+	app.logBuffer.write("GET /late 200\n")
+	app.logsReceived()
+	assert.NotContains(t, app.LogsView.GetText(true), "/late")
+	assert.Contains(t, app.LogsView.GetTitle(), "PAUSED +1")
+	app.searchLogs("users")
+	assert.NotContains(t, app.LogsView.GetText(true), "/late", "Searching while paused should not show new lines")
+	app.togglePauseLogs()
+	assert.Contains(t, app.LogsView.GetText(true), "GET /late 200")
+	assert.NotContains(t, app.LogsView.GetTitle(), "PAUSED")
+}
+
+// TestPauseLogsOverflow tests that the lines shown when pausing survive more than maxLogLines
+// lines arriving during the pause
+func TestPauseLogsOverflow(t *testing.T) {
+	app := NewApp()
+	app.logBuffer = &logBuffer{}
+	app.logBuffer.write("first\nsecond\n")
+	app.renderLogs()
+
+	app.togglePauseLogs()
+	var late strings.Builder
+	for i := 0; i < maxLogLines+5; i++ {
+		fmt.Fprintf(&late, "late %d\n", i)
+	}
+	app.logBuffer.write(late.String())
+	app.logsReceived()
+	app.renderLogs()
+	assert.Equal(t, "first\nsecond", app.LogsView.GetText(true), "The paused lines should stay shown")
+	assert.Contains(t, app.LogsView.GetTitle(), fmt.Sprintf("PAUSED +%d", maxLogLines+5))
+
+	app.togglePauseLogs()
+	text := app.LogsView.GetText(true)
+	assert.True(t, strings.HasPrefix(text, "late 5\n"), "Resuming should show the last maxLogLines lines")
+	assert.True(t, strings.HasSuffix(text, fmt.Sprintf("late %d", maxLogLines+4)))
+}
+
+func TestJSONLogs(t *testing.T) {
+	app := NewApp()
+	app.logBuffer = &logBuffer{}
+	app.logBuffer.write(`{"ts":"2024-05-01T10:00:00Z","level":"info","msg":"started","port":8080}` + "\n")
+	app.logBuffer.write(`{"time":"2024-05-01T10:00:01Z","level":"warn","msg":"slow request","path":"/api","ms":950}` + "\n")
+	app.logBuffer.write(`{"time":"2024-05-01T10:00:02Z","level":50,"msg":"failed","error":"connection refused"}` + "\n")
+	app.logBuffer.write("plain text line\n")
+	app.renderLogs()
+	assert.Equal(t, strings.Join([]string{
+		"2024-05-01T10:00:00Z INFO  started port=8080",
+		"2024-05-01T10:00:01Z WARN  slow request ms=950 path=/api",
+		`2024-05-01T10:00:02Z ERROR failed error="connection refused"`,
+		"plain text line",
+	}, "\n"), app.LogsView.GetText(true), "JSON lines should be formatted as time level msg key=value")
+	assert.Contains(t, app.LogsView.GetText(false), "[yellow]WARN", "Levels should be colored")
+
+	// Only the picked fields are shown, in order
+	app.setLogFields("level, msg,path")
+	assert.Equal(t, "INFO  started\nWARN  slow request path=/api\nERROR failed\nplain text line", app.LogsView.GetText(true))
+	app.setLogFields("")
+
+	// Field conditions compare levels by severity and numbers numerically, and hide other lines
+	require.NoError(t, app.setLogFieldFilter("level>=warn"))
+	assert.Equal(t, 2, strings.Count(app.LogsView.GetText(true), "\n")+1)
+	assert.NotContains(t, app.LogsView.GetText(true), "started")
+	require.NoError(t, app.setLogFieldFilter("ms>100 path~^/a"))
+	assert.Equal(t, "2024-05-01T10:00:01Z WARN  slow request ms=950 path=/api", app.LogsView.GetText(true))
+	assert.Contains(t, app.LogsView.GetTitle(), "where ms>100 path~^/a")
+	assert.Error(t, app.setLogFieldFilter("level>=loud"), "Unknown levels should be rejected")
+	assert.Error(t, app.setLogFieldFilter("level"), "Conditions without an operator should be rejected")
+	require.NoError(t, app.setLogFieldFilter(""))
+
+	// The raw lines can be shown again
+	app.toggleJSONLogs()
+	assert.Contains(t, app.LogsView.GetText(true), `{"ts":"2024-05-01T10:00:00Z","level":"info","msg":"started","port":8080}`)
+	assert.Contains(t, app.logOptionsBar.GetText(true), "json:off")
+
+	// Lines prefixed with a timestamp by the T option are still parsed
+	line := parseJSONLog(`2024-05-01T10:00:00.123Z {"msg":"hi"}`)
+	require.NotNil(t, line)
+	assert.Equal(t, "2024-05-01T10:00:00.123Z ", line.prefix)
+	assert.Nil(t, parseJSONLog(`GET {"msg":"hi"} 200`))
+
+	// Further aliases of a group are shown as other fields, with their own values
+	line = parseJSONLog(`{"time":"10:00:00","timestamp":"1714557600","msg":"hi","message":"hello"}`)
+	require.NotNil(t, line)
+	var text strings.Builder
+	for _, s := range line.spans(nil) {
+		text.WriteString(s.text)
+	}
+	assert.Equal(t, "10:00:00 hi message=hello timestamp=1714557600", text.String())
+}
+
+func TestAggregatedLogs(t *testing.T) {
+	running := corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		{Name: "sidecar", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+	}}
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "sidecar"}}},
+			Status:     running,
+		}
+	}
+	web := map[string]string{"app": "web"}
+
+	app := NewApp()
+	app.CurrentNs = "default"
+	app.KubeClient = fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: web}},
+		},
+		pod("web-1", web), pod("web-2", web), pod("db-0", map[string]string{"app": "db"}),
+	)
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeDeployment) }))
+	defer app.stopWatches()
+	defer app.stopLogStream()
+
+	app.showWorkloadLogs()
+	require.NotNil(t, app.aggregator)
+	sources := func() map[string]bool {
+		lines, _, _ := app.aggregator.buffer.snapshot()
+		found := make(map[string]bool)
+		for _, line := range lines {
+			found[line.source] = true
+		}
+		return found
+	}
+	assert.Eventually(t, func() bool {
+		return len(sources()) == 2 && sources()["web-1/app"] && sources()["web-2/app"]
+	}, 5*time.Second, 10*time.Millisecond, "Only running containers of the selected pods should be streamed")
+	assert.Eventually(t, func() bool { return app.aggregator.streamCount() == 0 }, 5*time.Second, 10*time.Millisecond,
+		"The fake streams end after their log, so none should be counted as open")
+
+	// Streams follow the pods, and the log of a container is not read again until it restarts
+	pods := app.KubeClient.CoreV1().Pods("default")
+	updated := pod("web-1", web)
+	updated.Annotations = map[string]string{"updated": "true"}
+	_, err := pods.Update(context.TODO(), updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, pods.Delete(context.TODO(), "web-2", metav1.DeleteOptions{}))
+	_, err = pods.Create(context.TODO(), pod("web-3", web), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return sources()["web-3/app"] }, 5*time.Second, 10*time.Millisecond)
+	lines, _, _ := app.aggregator.buffer.snapshot()
+	web1 := 0
+	for _, line := range lines {
+		if line.source == "web-1/app" {
+			web1++
+		}
+	}
+	assert.Equal(t, 1, web1, "Updating a pod should not stream its ended logs again")
+
+	app.renderLogs()
+	assert.Contains(t, app.LogsView.GetText(false), logSourceColor("web-1/app")+"web-1/app[-] fake logs")
+	assert.Contains(t, app.LogsView.GetTitle(), "deployment/web")
+
+	_, err = podSelector(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "headless"}})
+	assert.Error(t, err, "Services without a selector have no pods")
+	_, err = podSelector(&corev1.ConfigMap{})
+	assert.Error(t, err)
+}
+
+func TestSaveLogs(t *testing.T) {
+	app := NewApp()
+	app.logDir = t.TempDir()
+	app.CurrentContext = "arn:aws:eks:eu-west-1:1234:cluster/prod"
+	app.KubeClient = fake.NewSimpleClientset()
+	app.CurrentNs = "default"
+	app.SelectedPod = "web-1"
+	app.SelectedCont = "app"
+	app.logBuffer = &logBuffer{}
+	app.logBuffer.write("first line\nsecond line\npartial")
+
+	path, size, err := app.saveLogBuffer()
+	require.NoError(t, err)
+	dir := filepath.Join(app.logDir, "arn_aws_eks_eu-west-1_1234_cluster_prod", "default", "web-1", "app")
+	assert.Equal(t, dir, filepath.Dir(path), "Logs should be saved under context/namespace/pod/container")
+	assert.True(t, strings.HasSuffix(path, ".log"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line\npartial\n", string(data))
+	assert.Equal(t, int64(len(data)), size)
+
+	// The full log is fetched again, compressed when enabled
+	app.logOptions.gzip = true
+	save := app.saveContainerLog("default", "web-1", "app")
+	app.logOptions.gzip = false
+	path, _, err = save()
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, ".log.gz"), "The options should be those when the save started")
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	data, err = io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", string(data))
+
+	// Saves within the same second or millisecond get their own files
+	paths := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		saved, _, err := app.saveLogBuffer()
+		require.NoError(t, err)
+		paths[saved] = true
+	}
+	assert.Len(t, paths, 5)
+
+	app.showSavedLogs(path, 1536, nil)
+	assert.Contains(t, app.StatusBar.GetText(true), path+" (1.5 KiB)")
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "2.0 MiB", formatBytes(2*1024*1024))
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("logDir: /var/log/k8stui\ngzipLogs: true\n"), 0600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, Config{LogDir: "/var/log/k8stui", GzipLogs: true}, config)
+
+	app := NewAppWithOptions(Options{ConfigFile: path, LogDir: "/tmp/logs"})
+	assert.Equal(t, "/tmp/logs", app.logDir, "The flag should take precedence over the config file")
+	assert.True(t, app.logOptions.gzip)
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err, "A config file given explicitly must exist")
+	require.NoError(t, os.WriteFile(path, []byte("logdirectory: /tmp\n"), 0600))
+	_, err = LoadConfig(path)
+	assert.Error(t, err, "Unknown fields should be rejected")
+}
+
+// echoConnection is a port-forward connection whose data streams echo what is written to them
+type echoConnection struct {
+	closed chan bool
+}
+
+func (c *echoConnection) CreateStream(headers http.Header) (httpstream.Stream, error) {
+	r, w := io.Pipe()
+	if headers.Get(corev1.StreamType) != corev1.StreamTypeData {
+		w.Close()
+	}
+	return &echoStream{PipeReader: r, PipeWriter: w, headers: headers}, nil
+}
+func (c *echoConnection) Close() error                       { return nil }
+func (c *echoConnection) CloseChan() <-chan bool             { return c.closed }
+func (c *echoConnection) SetIdleTimeout(time.Duration)       {}
+func (c *echoConnection) RemoveStreams(...httpstream.Stream) {}
+
+type echoStream struct {
+	*io.PipeReader
+	*io.PipeWriter
+	headers http.Header
+}
+
+func (s *echoStream) Close() error         { return s.PipeWriter.Close() }
+func (s *echoStream) Reset() error         { return s.PipeWriter.Close() }
+func (s *echoStream) Headers() http.Header { return s.headers }
+func (s *echoStream) Identifier() uint32   { return 0 }
+
+type echoDialer struct{}
+
+func (echoDialer) Dial(...string) (httpstream.Connection, string, error) {
+	return &echoConnection{closed: make(chan bool)}, portforward.PortForwardProtocolV1Name, nil
+}
+
+func TestPortForward(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: web},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Selector: web,
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+
+	app := NewApp()
+	app.CurrentContext = "test"
+	app.CurrentNs = "default"
+	app.KubeClient = fake.NewSimpleClientset(pod("web-a", corev1.ConditionFalse), pod("web-b", corev1.ConditionTrue), svc)
+	var dialed []string
+	release := make(chan struct{})
+	app.forwardDialer = func(namespace, pod string) (httpstream.Dialer, error) {
+		<-release
+		dialed = append(dialed, namespace+"/"+pod)
+		return echoDialer{}, nil
+	}
+	app.updates = make(chan func(), 100)
+	defer app.stopPortForwards()
+
+	// Forwards start in the background and are reported on the UI goroutine
+	start := func(obj runtime.Object, ports string) (func() (*portForward, error), error) {
+		var f *portForward
+		var startErr error
+		done := false
+		err := app.startPortForward(obj, ports, func(forward *portForward, err error) {
+			f, startErr, done = forward, err, true
+		})
+		return func() (*portForward, error) {
+			for !done {
+				select {
+				case update := <-app.updates:
+					update()
+				case <-time.After(5 * time.Second):
+					t.Fatal("The forward should start or fail")
+				}
+			}
+			return f, startErr
+		}, err
+	}
+	forward := func(obj runtime.Object, ports string) (*portForward, error) {
+		wait, err := start(obj, ports)
+		if err != nil {
+			return nil, err
+		}
+		return wait()
+	}
+
+	// A service port is forwarded to the target port of a ready pod, without waiting for the
+	// connection
+	wait, err := start(svc, ":80")
+	require.NoError(t, err)
+	assert.Empty(t, app.portForwards, "The forward should not be listed before it starts")
+	close(release)
+	f, err := wait()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default/web-b"}, dialed)
+	assert.Equal(t, 8080, f.remote, "The named target port should be resolved in the pod")
+	assert.NotZero(t, f.local, "The local port picked should be known")
+	status, _ := f.state()
+	assert.Equal(t, forwardActive, status)
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", f.local))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+	conn.Close()
+	assert.Eventually(t, func() bool { return f.bytesIn.Load() == 4 && f.bytesOut.Load() == 4 }, time.Second, 10*time.Millisecond)
+
+	// Forwards outlive view changes and are listed with their traffic
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeDeployment) }))
+	defer app.stopWatches()
+	table := tview.NewTable()
+	app.renderPortForwards(table)
+	assert.Equal(t, "default/service/web", table.GetCell(1, 0).Text)
+	assert.Equal(t, "web-b", table.GetCell(1, 1).Text)
+	assert.Equal(t, "Active", table.GetCell(1, 4).Text)
+	assert.Equal(t, "4 B", table.GetCell(1, 5).Text)
+
+	_, err = forward(svc, "9090:81")
+	assert.Error(t, err, "Ports the service does not expose should be rejected")
+	_, err = forward(svc, "80:x")
+	assert.Error(t, err)
+
+	// Stopping closes the local port
+	app.stopPortForward(f)
+	assert.Empty(t, app.portForwards)
+	assert.Eventually(t, func() bool {
+		status, _ := f.state()
+		return status == forwardStopped
+	}, time.Second, 10*time.Millisecond)
+	_, err = net.Dial("tcp", fmt.Sprintf("localhost:%d", f.local))
+	assert.Error(t, err)
+	app.showPortForwards()
+	require.True(t, app.pages.HasPage("portforwards"))
+	pressKey(app, tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone))
+	assert.False(t, app.pages.HasPage("portforwards"), "q should close the panel")
+	assert.Nil(t, app.portForwardsRefresh, "Closing the panel should stop its refresh")
+
+	// Exiting stops the refresh of an open panel
+	app.showPortForwards()
+	refresh := app.portForwardsRefresh
+	require.NotNil(t, refresh)
+	app.stopPortForwards()
+	assert.Nil(t, app.portForwardsRefresh)
+	_, open := <-refresh
+	assert.False(t, open)
+}
+
+func TestScaleWorkload(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{Replicas: 3, ReadyReplicas: 3},
+	}
+	client := fake.NewSimpleClientset(deployment)
+
+	// The fake clientset has no scale subresource, so scales are applied to the deployment
+	var scales []int32
+	client.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateAction)
+		if update.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := update.GetObject().(*autoscalingv1.Scale)
+		scales = append(scales, scale.Spec.Replicas)
+		obj, err := client.Tracker().Get(appsv1.SchemeGroupVersion.WithResource("deployments"), "default", scale.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		d := obj.(*appsv1.Deployment).DeepCopy()
+		d.Spec.Replicas = &scale.Spec.Replicas
+		return true, scale, client.Tracker().Update(appsv1.SchemeGroupVersion.WithResource("deployments"), d, "default")
+	})
+
+	app := NewApp()
+	app.CurrentNs = "default"
+	app.KubeClient = client
+	require.NoError(t, load(t, app, func() error { return app.LoadResources(ResourceTypeDeployment) }))
+	defer app.stopWatches()
+	get := func() *appsv1.Deployment {
+		d, err := client.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+		require.NoError(t, err)
+		return d
+	}
+
+	// Scaling goes through the scale subresource and follows the ready replicas
+	kind, _ := lookupResourceKind(ResourceTypeDeployment)
+	require.NoError(t, app.scaleWorkload(kind, deployment, 5))
+	assert.Equal(t, []int32{5}, scales)
+	require.NotNil(t, app.followed)
+	assert.Contains(t, app.InfoView.GetText(true), "Ready: ")
+	assert.Contains(t, app.InfoView.GetText(true), " 3/5")
+
+	scaled := get()
+	scaled.Status.Replicas, scaled.Status.ReadyReplicas = 5, 5
+	app.renderFollowed(scaled)
+	assert.Contains(t, app.InfoView.GetText(true), "Scaled to 5 replicas")
+	assert.Nil(t, app.followed, "Progress should stop being followed once the replicas are ready")
+
+	// Scaling to zero records the replicas, which are restored by the same toggle
+	app.ResourceList.SelectKey("default/web")
+	app.ResourceList.Render()
+	require.Eventually(t, func() bool {
+		_, obj, ok := app.selectedObject()
+		return ok && *obj.(*appsv1.Deployment).Spec.Replicas == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	app.toggleScaleToZero()
+	assert.Equal(t, []int32{5, 0}, scales)
+	assert.Equal(t, "5", get().Annotations[previousReplicasAnnotation])
+
+	require.Eventually(t, func() bool {
+		_, obj, ok := app.selectedObject()
+		return ok && *obj.(*appsv1.Deployment).Spec.Replicas == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	app.toggleScaleToZero()
+	assert.Equal(t, []int32{5, 0, 5}, scales)
+	assert.NotContains(t, get().Annotations, previousReplicasAnnotation, "The recorded replicas should be removed once restored")
+
+	app.ReadOnly = true
+	app.App.SetFocus(app.ResourceList)
+	assert.Nil(t, app.App.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 'z', tcell.ModNone)))
+	assert.Len(t, scales, 3, "Read-only mode should refuse scaling")
+	assert.Contains(t, app.StatusBar.GetText(true), "Read-only mode: scaling is disabled")
+}
+
+func TestRollouts(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	template := func(image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: web},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+		}
+	}
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", Generation: 2,
+			Annotations: map[string]string{revisionAnnotation: "2"}},
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas, Selector: &metav1.LabelSelector{MatchLabels: web}, Template: template("nginx:1.25")},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+	}
+	owner := []metav1.OwnerReference{*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment"))}
+	replicaSet := func(name, revision, cause, image string) *appsv1.ReplicaSet {
+		tmpl := template(image)
+		tmpl.Labels = map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: name}
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: web, OwnerReferences: owner,
+				Annotations: map[string]string{revisionAnnotation: revision, changeCauseAnnotation: cause}},
+			Spec: appsv1.ReplicaSetSpec{Template: tmpl},
+		}
+	}
+	client := fake.NewSimpleClientset(deployment,
+		replicaSet("web-1", "1", "initial release", "nginx:1.24"),
+		replicaSet("web-2", "2", "bump nginx", "nginx:1.25"),
+		// Not owned by the deployment
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: web,
+			Annotations: map[string]string{revisionAnnotation: "7"}}},
+	)
+
+	app := NewApp()
+	app.CurrentNs = "default"
+	app.KubeClient = client
+	kind, _ := lookupResourceKind(ResourceTypeDeployment)
+	get := func() *appsv1.Deployment {
+		d, err := client.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+		require.NoError(t, err)
+		return d
+	}
+
+	// History is built from the owned replicasets, newest first
+	revisions, err := app.rolloutHistory(deployment)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, int64(2), revisions[0].number)
+	assert.True(t, revisions[0].current)
+	assert.Equal(t, "initial release", revisions[1].changeCause)
+	assert.Equal(t, []string{"nginx:1.24"}, revisions[1].images)
+
+	// Undo restores the pod template of the revision, without the replicaset hash label
+	app.undoRollout(kind, deployment, revisions[1])
+	undone := get()
+	assert.Equal(t, "nginx:1.24", undone.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, web, undone.Spec.Template.Labels)
+	assert.Equal(t, " Rollout web ", app.InfoView.GetTitle(), "The rollout should be followed after an undo")
+
+	// Restart changes the restartedAt annotation of the pod template
+	app.restartRollout(kind, deployment)
+	assert.NotEmpty(t, get().Spec.Template.Annotations[restartedAtAnnotation])
+	assert.Contains(t, app.InfoView.GetText(true), "successfully rolled out")
+	assert.Nil(t, app.followed, "A finished rollout should not be followed")
+
+	app.setRolloutPaused(kind, deployment, true)
+	assert.True(t, get().Spec.Paused)
+	app.setRolloutPaused(kind, deployment, false)
+	assert.False(t, get().Spec.Paused)
+
+	// The status tracks the replicas until the rollout completes or misses its deadline
+	progressing := deployment.DeepCopy()
+	progressing.Status.UpdatedReplicas, progressing.Status.AvailableReplicas = 1, 1
+	app.followRollout(kind, progressing)
+	assert.Contains(t, app.InfoView.GetText(true), "1 out of 2 new replicas have been updated")
+	require.NotNil(t, app.followed)
+	progressing.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}}
+	app.renderFollowed(progressing)
+	assert.Contains(t, app.InfoView.GetText(true), "exceeded its progress deadline")
+	assert.Nil(t, app.followed)
+
+	deadline := int32(0)
+	progressing.Spec.ProgressDeadlineSeconds = &deadline
+	progressing.Status.Conditions = nil
+	assert.True(t, app.renderRolloutStatus(kind, progressing, time.Now().Add(-time.Second)), "Rollouts taking longer than their deadline should stop being followed")
+
+	// Statefulset revisions are controller revisions, restored by applying their patch
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "db-uid"},
+		Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: web}, Template: template("postgres:16")},
+	}
+	stsOwner := []metav1.OwnerReference{*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))}
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "default", Labels: web, OwnerReferences: stsOwner},
+		Revision:   1,
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"app","image":"postgres:15"}]}}}}`)},
+	}
+	require.NoError(t, client.Tracker().Add(sts))
+	require.NoError(t, client.Tracker().Add(revision))
+	stsKind, _ := lookupResourceKind(ResourceTypeStatefulSet)
+	revisions, err = app.rolloutHistory(sts)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, []string{"postgres:15"}, revisions[0].images)
+	app.undoRollout(stsKind, sts, revisions[0])
+	restored, err := client.AppsV1().StatefulSets("default").Get(context.Background(), "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres:15", restored.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestDiff(t *testing.T) {
+	app := NewApp()
+
+	// The live object is compared with its last applied configuration, without server fields
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name": "settings", "namespace": "default", "uid": "1234", "resourceVersion": "42",
+			"annotations": map[string]interface{}{
+				lastAppliedAnnotation: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"default"},"data":{"mode":"fast"}}`,
+			},
+		},
+		"data": map[string]interface{}{"mode": "slow"},
+	}}
+	applied, current, err := lastAppliedDiffTexts(live)
+	require.NoError(t, err)
+	assert.NotContains(t, current, "uid")
+	assert.NotContains(t, current, "annotations")
+	require.NoError(t, app.showDiff("configmap/settings", applied, current, "last-applied", "live"))
+	text := app.InfoView.GetText(true)
+	assert.Contains(t, text, "-  mode: fast")
+	assert.Contains(t, text, "+  mode: slow")
+	assert.Equal(t, 3, app.CurrentFocus)
+
+	delete(live.Object["metadata"].(map[string]interface{}), "annotations")
+	_, _, err = lastAppliedDiffTexts(live)
+	assert.Error(t, err)
+
+	// n/N cycle through the hunks
+	var from, to []string
+	for i := 0; i < 30; i++ {
+		from = append(from, fmt.Sprintf("line %d", i))
+	}
+	to = append(to, from...)
+	to[2], to[25] = "changed 2", "changed 25"
+	require.NoError(t, app.showDiff("lines", strings.Join(from, "\n"), strings.Join(to, "\n"), "a", "b"))
+	assert.Equal(t, 2, app.diff.hunks)
+	assert.Equal(t, " Diff lines [1/2] ", app.InfoView.GetTitle())
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone))
+	assert.Equal(t, " Diff lines [2/2] ", app.InfoView.GetTitle())
+	assert.Equal(t, []string{"hunk-1"}, app.InfoView.GetHighlights())
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyRune, 'N', tcell.ModNone))
+	assert.Equal(t, 0, app.diff.current)
+
+	// Revisions are compared on their pod templates, ignoring the replicaset hash label
+	kind, _ := lookupResourceKind(ResourceTypeDeployment)
+	revision := func(number int64, hash, image string) rolloutRevision {
+		return rolloutRevision{number: number, template: &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: hash}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+		}}
+	}
+	app.diffRevisions(kind, "web", revision(1, "abc", "nginx:1.24"), revision(2, "def", "nginx:1.25"))
+	text = app.InfoView.GetText(true)
+	assert.Contains(t, text, "-    - image: nginx:1.24")
+	assert.Contains(t, text, "+    - image: nginx:1.25")
+	assert.NotContains(t, text, "abc")
+
+	// Esc leaves the diff
+	app.handleInfoKey(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	assert.Nil(t, app.diff)
+	assert.Equal(t, " Info ", app.InfoView.GetTitle())
+	assert.Equal(t, 2, app.CurrentFocus)
+}
+
+func TestReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("contexts:\n  prod:\n    readOnly: true\n"), 0600))
+	app := NewAppWithOptions(Options{ConfigFile: path})
+	app.CurrentContext = "staging"
+	assert.False(t, app.isReadOnly())
+
+	// The setting of the context applies once it is selected, hiding the actions it refuses
+	app.CurrentContext = "prod"
+	assert.True(t, app.isReadOnly())
+	app.updateTitle()
+	title := app.grid.GetTitle()
+	assert.Contains(t, title, "READ-ONLY")
+	assert.Contains(t, title, "Describe")
+	for _, label := range []string{"Delete", "Shell", "Edit", "Port-forward", "Scale", "Rollout"} {
+		assert.NotContains(t, title, "[::-] "+label+" ", "%s should be hidden in read-only mode", label)
+	}
+
+	// Every key that modifies the cluster is refused before reaching its handler
+	capture := app.App.GetInputCapture()
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyCtrlD, 0, tcell.ModNone)))
+	assert.Contains(t, app.StatusBar.GetText(true), "Read-only mode: deleting resources is disabled")
+	assert.False(t, app.pages.HasPage("confirmation"), "No deletion should be confirmed")
+	app.App.SetFocus(app.ResourceList)
+	capture(tcell.NewEventKey(tcell.KeyRune, 'E', tcell.ModNone))
+	assert.Contains(t, app.StatusBar.GetText(true), "editing resources")
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, 'o', tcell.ModNone)))
+	assert.Contains(t, app.StatusBar.GetText(true), "changing rollouts")
+	assert.False(t, app.pages.HasPage("rollout"), "No rollout menu should open")
+	app.viewingContainers = true
+	capture(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	assert.Contains(t, app.StatusBar.GetText(true), "exec into containers")
+	assert.NotNil(t, capture(tcell.NewEventKey(tcell.KeyRune, 'l', tcell.ModNone)), "Reading keys should pass")
+
+	// Requests that could modify the cluster never leave the client
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+	}))
+	defer server.Close()
+	require.NoError(t, app.setKubeClient(&rest.Config{Host: server.URL}))
+	_, _ = app.KubeClient.CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{})
+	err := app.KubeClient.CoreV1().Pods("default").Delete(context.Background(), "web", metav1.DeleteOptions{})
+	assert.ErrorContains(t, err, "read-only mode")
+	assert.Equal(t, []string{http.MethodGet}, methods)
+
+	app.CurrentContext = "staging"
+	assert.NoError(t, app.KubeClient.CoreV1().Pods("default").Delete(context.Background(), "web", metav1.DeleteOptions{}))
+	assert.Equal(t, []string{http.MethodGet, http.MethodDelete}, methods)
+}
+
+func TestProtectedContexts(t *testing.T) {
+	assert.True(t, matchContextPattern("*prod*", "arn:aws:eks:eu-west-1:123:cluster/prod-eu"))
+	assert.True(t, matchContextPattern("gke_?_prod", "gke_a_prod"))
+	assert.False(t, matchContextPattern("*prod*", "staging"))
+	assert.False(t, matchContextPattern("prod", "prod-eu"), "Patterns should match the whole name")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("protectedContexts: ['*prod*']\ncontexts:\n  prod-sandbox:\n    protected: false\n"), 0600))
+	app := NewAppWithOptions(Options{ConfigFile: path})
+	app.KubeClient = fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}})
+	app.CurrentContext = "prod-sandbox"
+	assert.False(t, app.isProtected(), "The context setting should override the patterns")
+	app.CurrentContext = "prod-eu"
+	require.True(t, app.isProtected())
+	app.updateTitle()
+	assert.Contains(t, app.grid.GetTitle(), "PROTECTED: prod-eu")
+	assert.Equal(t, protectedColor, app.grid.GetBorderColor())
+
+	// Deleting requires typing the name of the namespace
+	namespaces := func() int {
+		list, err := app.KubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		return len(list.Items)
+	}
+	confirm := func(text string) {
+		input, ok := app.App.GetFocus().(*tview.InputField)
+		require.True(t, ok, "The name should be typed in an input field")
+		input.SetText(text)
+		input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	}
+	app.CurrentFocus, app.SelectedNs = 0, "web"
+	app.deleteCurrentResource()
+	confirm("yes")
+	assert.Equal(t, 1, namespaces(), "A wrong name should cancel the deletion")
+	assert.Contains(t, app.StatusBar.GetText(true), "does not match web")
+	assert.False(t, app.pages.HasPage("confirmation"))
+
+	app.deleteCurrentResource()
+	confirm("web")
+	assert.Equal(t, 0, namespaces())
+
+	// Outside protected contexts a Yes/No modal is enough
+	app.CurrentContext = "staging"
+	app.SelectedNs = "web"
+	app.deleteCurrentResource()
+	assert.True(t, app.pages.HasPage("confirmation"))
+	_, typed := app.App.GetFocus().(*tview.InputField)
+	assert.False(t, typed)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the k8stui configuration file. Command-line flags take precedence over it.
+type Config struct {
+	LogDir            string                   `json:"logDir,omitempty"`            // Directory logs are saved under
+	GzipLogs          bool                     `json:"gzipLogs,omitempty"`          // Compress saved logs
+	ReadOnly          bool                     `json:"readOnly,omitempty"`          // Refuse every action that modifies the cluster
+	Contexts          map[string]ContextConfig `json:"contexts,omitempty"`          // Settings of individual kubeconfig contexts
+	ProtectedContexts []string                 `json:"protectedContexts,omitempty"` // Context patterns, like *prod*, where destructive actions require typing the resource name
+}
+
+// ContextConfig holds the settings of a kubeconfig context, which override the global ones
+type ContextConfig struct {
+	ReadOnly  *bool `json:"readOnly,omitempty"`
+	Protected *bool `json:"protected,omitempty"`
+}
+
+// DefaultConfigPath returns $XDG_CONFIG_HOME/k8stui/config.yaml, or its platform equivalent
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "k8stui", "config.yaml")
+}
+
+// LoadConfig reads a configuration file. An empty path reads the default file, which may
+// not exist.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	explicit := path != ""
+	if !explicit {
+		path = DefaultConfigPath()
+	}
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("error reading config file: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+	config.LogDir = expandHome(config.LogDir)
+	return config, nil
+}
+
+// expandHome replaces a leading ~ in a path with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// defaultLogDir is where logs are saved when neither a flag nor the config file sets it
+func defaultLogDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "k8stui-logs")
+	}
+	return "k8stui-logs"
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
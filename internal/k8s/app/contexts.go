@@ -0,0 +1,139 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ContextState is the view a user last had open in a kube context
+type ContextState struct {
+	Namespace     string
+	AllNamespaces bool
+	ResourceType  ResourceType
+}
+
+// showContextPicker displays a list of every context in the merged kubeconfig
+func (a *App) showContextPicker() {
+	if a.loadingRules == nil {
+		a.showError("No kubeconfig loaded: context switching is unavailable")
+		return
+	}
+
+	rawConfig, err := a.loadingRules.Load()
+	if err != nil {
+		a.showError(fmt.Sprintf("Error loading kubeconfig: %v", err))
+		return
+	}
+
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(" Contexts ")
+	for i, name := range names {
+		kubeContext := rawConfig.Contexts[name]
+		namespace := kubeContext.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		mainText := tview.Escape(name)
+		if name == a.CurrentContext {
+			mainText = "[green]* " + mainText
+		}
+		info := fmt.Sprintf("cluster: %s | user: %s | namespace: %s", kubeContext.Cluster, kubeContext.AuthInfo, namespace)
+
+		list.AddItem(mainText, tview.Escape(info), 0, func() {
+			a.closeContextPicker()
+			if err := a.switchContext(name); err != nil {
+				a.showError(fmt.Sprintf("Error switching context: %v", err))
+			}
+		})
+		if name == a.CurrentContext {
+			list.SetCurrentItem(i)
+		}
+	}
+	list.SetDoneFunc(a.closeContextPicker)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && (event.Rune() == 'q' || event.Rune() == 'Q') {
+			a.closeContextPicker()
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("contexts", modalFrame(list, 80, 2*len(names)+2), true, true)
+	a.App.SetFocus(list)
+}
+
+// closeContextPicker removes the context picker and restores focus
+func (a *App) closeContextPicker() {
+	a.pages.RemovePage("contexts")
+	a.App.SetFocus(a.getCurrentFocus())
+}
+
+// switchContext rebuilds the Kubernetes clients for another kubeconfig context and
+// returns to the namespace and resource type last used in that context
+func (a *App) switchContext(name string) error {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(a.loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: name})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("error loading context %s: %v", name, err)
+	}
+	if err := a.setKubeClient(config); err != nil {
+		return err
+	}
+
+	// Remember where we were and drop everything bound to the old cluster
+	a.saveContextState()
+	a.stopWatches()
+	a.stopLogStream()
+	a.LogsView.Clear()
+	a.InfoView.Clear()
+	a.SelectedPod = ""
+	a.SelectedPodNs = ""
+	a.SelectedCont = ""
+	a.SelectedResource = ""
+	a.CurrentContext = name
+
+	state, ok := a.contextStates[name]
+	if !ok {
+		if rawConfig, err := clientConfig.RawConfig(); err == nil && rawConfig.Contexts[name] != nil {
+			state.Namespace = rawConfig.Contexts[name].Namespace
+		}
+	}
+	a.CurrentNs = state.Namespace
+	a.SelectedNs = state.Namespace
+	a.allNamespaces = state.AllNamespaces
+	a.SelectedResourceType = state.ResourceType
+
+	a.updateTitle()
+	_ = a.discoverResourceKinds()
+	if err := a.LoadNamespaces(); err != nil {
+		return err
+	}
+	return a.openCurrentView()
+}
+
+// saveContextState records the current view of the active context
+func (a *App) saveContextState() {
+	if a.CurrentContext == "" {
+		return
+	}
+	if a.contextStates == nil {
+		a.contextStates = make(map[string]ContextState)
+	}
+	a.contextStates[a.CurrentContext] = ContextState{
+		Namespace:     a.CurrentNs,
+		AllNamespaces: a.allNamespaces,
+		ResourceType:  a.SelectedResourceType,
+	}
+}
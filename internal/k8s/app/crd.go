@@ -0,0 +1,278 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// crdGVR is the resource holding CustomResourceDefinitions, read through the dynamic client
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// printerColumn is an additionalPrinterColumns entry of a served CRD version
+type printerColumn struct {
+	Name     string
+	Type     string
+	JSONPath string
+	Priority int64
+}
+
+// discoverResourceKinds finds the listable resources served by the cluster that are not in the
+// registry, such as custom resources, and adds them to ResourceTypeList grouped by API group
+func (a *App) discoverResourceKinds() error {
+	a.discoveredKinds = nil
+	defer a.initResourceTypes()
+
+	if a.KubeClient == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+
+	// Discovery reports unavailable groups as an error next to the groups that did respond
+	lists, err := discovery.ServerPreferredResources(a.KubeClient.Discovery())
+	if err != nil && len(lists) == 0 {
+		return fmt.Errorf("error discovering resources: %v", err)
+	}
+
+	columns := a.loadPrinterColumns()
+
+	var kinds []*ResourceKind
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			gvr := gv.WithResource(resource.Name)
+			if strings.Contains(resource.Name, "/") || !hasVerbs(resource.Verbs, "list", "watch") || isRegisteredResource(gvr) {
+				continue
+			}
+			kinds = append(kinds, newDynamicKind(gvr, resource.Kind, resource.Namespaced, columns[gvr]))
+		}
+	}
+
+	sort.Slice(kinds, func(i, j int) bool {
+		if kinds[i].GVR.Group != kinds[j].GVR.Group {
+			return kinds[i].GVR.Group < kinds[j].GVR.Group
+		}
+		return kinds[i].DisplayName < kinds[j].DisplayName
+	})
+	a.discoveredKinds = kinds
+	return nil
+}
+
+// loadPrinterColumns returns the list columns declared by each served CRD version. Clusters
+// where CRDs cannot be read simply show custom resources without extra columns.
+func (a *App) loadPrinterColumns() map[schema.GroupVersionResource][]printerColumn {
+	columns := make(map[schema.GroupVersionResource][]printerColumn)
+	if a.DynamicClient == nil {
+		return columns
+	}
+
+	crds, err := a.DynamicClient.Resource(crdGVR).List(a.getContext(), metav1.ListOptions{})
+	if err != nil {
+		return columns
+	}
+
+	for _, crd := range crds.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+
+		for _, v := range versions {
+			version, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(version, "name")
+			entries, _, _ := unstructured.NestedSlice(version, "additionalPrinterColumns")
+
+			gvr := schema.GroupVersionResource{Group: group, Version: name, Resource: plural}
+			for _, e := range entries {
+				entry, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				var column printerColumn
+				column.Name, _, _ = unstructured.NestedString(entry, "name")
+				column.Type, _, _ = unstructured.NestedString(entry, "type")
+				column.JSONPath, _, _ = unstructured.NestedString(entry, "jsonPath")
+				column.Priority, _, _ = unstructured.NestedInt64(entry, "priority")
+				columns[gvr] = append(columns[gvr], column)
+			}
+		}
+	}
+	return columns
+}
+
+// newDynamicKind builds a registry entry for a discovered resource, with list columns taken
+// from the CRD's printer columns and always ending in AGE
+func newDynamicKind(gvr schema.GroupVersionResource, kindName string, namespaced bool, printerColumns []printerColumn) *ResourceKind {
+	resourceType := ResourceType(gvr.Resource)
+	if gvr.Group != "" {
+		resourceType = ResourceType(gvr.Resource + "." + gvr.Group)
+	}
+
+	// Only the default columns are listed, as kubectl does without -o wide
+	var shown []printerColumn
+	var headers []string
+	for _, column := range printerColumns {
+		if column.Priority > 0 || strings.EqualFold(column.Name, "AGE") {
+			continue
+		}
+		shown = append(shown, column)
+		headers = append(headers, strings.ToUpper(column.Name))
+	}
+	headers = append(headers, "AGE")
+
+	kind := &ResourceKind{
+		Type:        resourceType,
+		DisplayName: kindName,
+		GVR:         gvr,
+		Namespaced:  namespaced,
+		Dynamic:     true,
+		Columns:     headers,
+	}
+	kind.Row = func(obj runtime.Object) []string {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		row := make([]string, 0, len(shown)+1)
+		for _, column := range shown {
+			row = append(row, printerColumnValue(u, column))
+		}
+		return append(row, getAge(u.GetCreationTimestamp().Time))
+	}
+	kind.Detail = func(a *App, obj runtime.Object) error {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", obj)
+		}
+		return a.showUnstructuredInfo(kind, u)
+	}
+	return kind
+}
+
+// printerColumnValue evaluates the JSONPath of a printer column against an object
+func printerColumnValue(obj *unstructured.Unstructured, column printerColumn) string {
+	path := jsonpath.New(column.Name).AllowMissingKeys(true)
+	if err := path.Parse(fmt.Sprintf("{%s}", column.JSONPath)); err != nil {
+		return "<invalid>"
+	}
+
+	var out bytes.Buffer
+	if err := path.Execute(&out, obj.Object); err != nil {
+		return "<error>"
+	}
+	value := out.String()
+	if value == "" {
+		return "<none>"
+	}
+
+	if column.Type == "date" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return getAge(t)
+		}
+	}
+	return value
+}
+
+// showUnstructuredInfo displays an object read through the dynamic client: its metadata and
+// printer columns followed by the full spec and status
+func (a *App) showUnstructuredInfo(kind *ResourceKind, obj *unstructured.Unstructured) error {
+	var status strings.Builder
+	if err := writeObjectInfo(&status, kind, obj); err != nil {
+		return err
+	}
+
+	if spec, ok := obj.Object["spec"]; ok {
+		status.WriteString("\n[green]Spec:\n")
+		writeUnstructuredValue(&status, spec, 1)
+	}
+	if objStatus, ok := obj.Object["status"]; ok {
+		status.WriteString("\n[green]Status:\n")
+		writeUnstructuredValue(&status, objStatus, 1)
+	}
+
+	a.InfoView.SetText(status.String())
+	return nil
+}
+
+// writeUnstructuredValue writes a nested unstructured value as indented key/value lines
+func writeUnstructuredValue(b *strings.Builder, value interface{}, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if isScalar(v[k]) {
+				b.WriteString(fmt.Sprintf("%s[yellow]%s[white]: %s\n", indent, k, tview.Escape(fmt.Sprint(v[k]))))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("%s[yellow]%s[white]:\n", indent, k))
+			writeUnstructuredValue(b, v[k], depth+1)
+		}
+	case []interface{}:
+		for _, item := range v {
+			if isScalar(item) {
+				b.WriteString(fmt.Sprintf("%s- %s\n", indent, tview.Escape(fmt.Sprint(item))))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("%s-\n", indent))
+			writeUnstructuredValue(b, item, depth+1)
+		}
+	default:
+		b.WriteString(fmt.Sprintf("%s%s\n", indent, tview.Escape(fmt.Sprint(v))))
+	}
+}
+
+// isScalar reports whether an unstructured value fits on a single line
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+// hasVerbs reports whether every wanted verb is supported by a resource
+func hasVerbs(verbs []string, wanted ...string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, v := range verbs {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isRegisteredResource reports whether a resource is already served by a built-in kind
+func isRegisteredResource(gvr schema.GroupVersionResource) bool {
+	for _, kind := range resourceKinds {
+		if kind.GVR.GroupResource() == gvr.GroupResource() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,369 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// podSpecPaths are where the pod spec lives in the objects that run pods
+var podSpecPaths = [][]string{
+	{"spec", "template", "spec"},                        // Deployments, StatefulSets, DaemonSets, ReplicaSets, Jobs
+	{"spec", "jobTemplate", "spec", "template", "spec"}, // CronJobs
+}
+
+// describeSelected shows a kubectl describe-like view of the object highlighted in the resource table
+func (a *App) describeSelected() {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+
+	text, err := a.describeObject(kind, obj)
+	if err != nil {
+		a.showError(fmt.Sprintf("Error describing object: %v", err))
+		return
+	}
+
+	a.leaveManifest()
+	a.leaveDiff()
+	a.InfoView.SetTitle(fmt.Sprintf(" Describe %s ", kind.DisplayName))
+	a.InfoView.SetText(text)
+	a.InfoView.ScrollToBeginning()
+	a.CurrentFocus = 3
+	a.UpdateFocus()
+}
+
+// describeObject renders the metadata, owners, conditions, pod details and events of an object
+func (a *App) describeObject(kind *ResourceKind, obj runtime.Object) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return "", fmt.Errorf("error converting object: %v", err)
+	}
+
+	var b strings.Builder
+	writeField(&b, "Name", accessor.GetName())
+	if accessor.GetNamespace() != "" {
+		writeField(&b, "Namespace", accessor.GetNamespace())
+	}
+	writeField(&b, "Kind", kind.DisplayName)
+	writeField(&b, "Created", fmt.Sprintf("%s (%s ago)", accessor.GetCreationTimestamp().Format(time.RFC3339), getAge(accessor.GetCreationTimestamp().Time)))
+	writeMap(&b, "Labels", accessor.GetLabels())
+	writeMap(&b, "Annotations", accessor.GetAnnotations())
+
+	if owners := accessor.GetOwnerReferences(); len(owners) > 0 {
+		b.WriteString("\n[green]Owner References:\n")
+		for _, owner := range owners {
+			controller := ""
+			if owner.Controller != nil && *owner.Controller {
+				controller = " (controller)"
+			}
+			b.WriteString(fmt.Sprintf("  [yellow]%s[white]: %s%s\n", owner.Kind, tview.Escape(owner.Name), controller))
+		}
+	}
+
+	writeConditions(&b, content)
+
+	if pod, ok := obj.(*corev1.Pod); ok {
+		writeField(&b, "\nNode", pod.Spec.NodeName)
+		writeField(&b, "IP", pod.Status.PodIP)
+		writeField(&b, "QoS Class", string(pod.Status.QOSClass))
+		writeContainers(&b, "Init Containers", pod.Spec.InitContainers, pod.Status.InitContainerStatuses)
+		writeContainers(&b, "Containers", pod.Spec.Containers, pod.Status.ContainerStatuses)
+		writePodSpec(&b, &pod.Spec)
+	} else {
+		for _, path := range podSpecPaths {
+			if template, found, _ := unstructured.NestedMap(content, path...); found {
+				var spec corev1.PodSpec
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, &spec); err == nil {
+					writeContainers(&b, "Containers", spec.Containers, nil)
+					writePodSpec(&b, &spec)
+				}
+				break
+			}
+		}
+	}
+
+	a.writeEvents(&b, obj)
+	return b.String(), nil
+}
+
+// writeField writes a labelled value; an empty value is shown as <none>
+func writeField(b *strings.Builder, label, value string) {
+	if value == "" {
+		value = "<none>"
+	}
+	b.WriteString(fmt.Sprintf("[green]%s: [white]%s\n", label, tview.Escape(value)))
+}
+
+// writeMap writes a section of sorted key/value pairs
+func writeMap(b *strings.Builder, label string, values map[string]string) {
+	if len(values) == 0 {
+		writeField(b, label, "")
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteString(fmt.Sprintf("[green]%s:\n", label))
+	for _, k := range keys {
+		value := values[k]
+		if len(value) > 120 {
+			value = value[:117] + "..."
+		}
+		b.WriteString(fmt.Sprintf("  [yellow]%s[white]: %s\n", tview.Escape(k), tview.Escape(value)))
+	}
+}
+
+// writeConditions writes status.conditions, which most kinds share
+func writeConditions(b *strings.Builder, content map[string]interface{}) {
+	conditions, found, _ := unstructured.NestedSlice(content, "status", "conditions")
+	if !found || len(conditions) == 0 {
+		return
+	}
+
+	b.WriteString("\n[green]Conditions:\n")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+
+		color := "[white]"
+		if status == "False" {
+			color = "[red]"
+		}
+		line := fmt.Sprintf("  [yellow]%s[white]: %s%s[white]", conditionType, color, status)
+		if reason != "" {
+			line += " (" + tview.Escape(reason) + ")"
+		}
+		if message != "" {
+			line += " " + tview.Escape(message)
+		}
+		b.WriteString(line + "\n")
+	}
+}
+
+// writeContainers writes the containers of a pod with their current and last states
+func writeContainers(b *strings.Builder, label string, containers []corev1.Container, statuses []corev1.ContainerStatus) {
+	if len(containers) == 0 {
+		return
+	}
+	byName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+
+	b.WriteString(fmt.Sprintf("\n[green]%s:\n", label))
+	for _, container := range containers {
+		b.WriteString(fmt.Sprintf("  [yellow]%s[white]:\n", tview.Escape(container.Name)))
+		b.WriteString(fmt.Sprintf("    Image: %s\n", tview.Escape(container.Image)))
+
+		status, ok := byName[container.Name]
+		if !ok {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("    State: %s\n", describeContainerState(status.State)))
+		if status.LastTerminationState.Terminated != nil {
+			b.WriteString(fmt.Sprintf("    Last State: %s\n", describeContainerState(status.LastTerminationState)))
+		}
+		b.WriteString(fmt.Sprintf("    Ready: %t\n", status.Ready))
+		b.WriteString(fmt.Sprintf("    Restart Count: %d\n", status.RestartCount))
+	}
+}
+
+// describeContainerState formats a container state with its reason, exit code and timing
+func describeContainerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return fmt.Sprintf("[green]Running[white] since %s", state.Running.StartedAt.Format(time.RFC3339))
+	case state.Waiting != nil:
+		text := "[yellow]Waiting[white]"
+		if state.Waiting.Reason != "" {
+			text += " (" + tview.Escape(state.Waiting.Reason) + ")"
+		}
+		if state.Waiting.Message != "" {
+			text += ": " + tview.Escape(state.Waiting.Message)
+		}
+		return text
+	case state.Terminated != nil:
+		t := state.Terminated
+		color := "[green]"
+		if t.ExitCode != 0 {
+			color = "[red]"
+		}
+		text := fmt.Sprintf("%sTerminated[white] (%s, exit code %d)", color, tview.Escape(t.Reason), t.ExitCode)
+		if !t.FinishedAt.IsZero() {
+			text += " at " + t.FinishedAt.Format(time.RFC3339)
+		}
+		if t.Message != "" {
+			text += ": " + tview.Escape(t.Message)
+		}
+		return text
+	}
+	return "<unknown>"
+}
+
+// writePodSpec writes the volumes and tolerations of a pod spec
+func writePodSpec(b *strings.Builder, spec *corev1.PodSpec) {
+	if len(spec.Volumes) > 0 {
+		b.WriteString("\n[green]Volumes:\n")
+		for _, volume := range spec.Volumes {
+			b.WriteString(fmt.Sprintf("  [yellow]%s[white]: %s\n", tview.Escape(volume.Name), volumeSource(volume.VolumeSource)))
+		}
+	}
+
+	if len(spec.Tolerations) > 0 {
+		b.WriteString("\n[green]Tolerations:\n")
+		for _, toleration := range spec.Tolerations {
+			text := toleration.Key
+			if toleration.Operator == corev1.TolerationOpExists {
+				text += " op=Exists"
+			} else if toleration.Value != "" {
+				text += "=" + toleration.Value
+			}
+			if toleration.Effect != "" {
+				text += ":" + string(toleration.Effect)
+			}
+			if toleration.TolerationSeconds != nil {
+				text += fmt.Sprintf(" for %ds", *toleration.TolerationSeconds)
+			}
+			b.WriteString("  " + tview.Escape(strings.TrimSpace(text)) + "\n")
+		}
+	}
+}
+
+// volumeSource names the source of a volume along with what it refers to
+func volumeSource(source corev1.VolumeSource) string {
+	switch {
+	case source.ConfigMap != nil:
+		return "ConfigMap " + source.ConfigMap.Name
+	case source.Secret != nil:
+		return "Secret " + source.Secret.SecretName
+	case source.PersistentVolumeClaim != nil:
+		return "PersistentVolumeClaim " + source.PersistentVolumeClaim.ClaimName
+	case source.EmptyDir != nil:
+		return "EmptyDir"
+	case source.HostPath != nil:
+		return "HostPath " + source.HostPath.Path
+	case source.Projected != nil:
+		return "Projected"
+	case source.DownwardAPI != nil:
+		return "DownwardAPI"
+	case source.CSI != nil:
+		return "CSI " + source.CSI.Driver
+	case source.NFS != nil:
+		return "NFS " + source.NFS.Server + ":" + source.NFS.Path
+	}
+	return "<other>"
+}
+
+// writeEvents writes the events about an object, most recent last
+func (a *App) writeEvents(b *strings.Builder, obj runtime.Object) {
+	events, err := a.objectEvents(obj)
+	b.WriteString("\n[green]Events:\n")
+	switch {
+	case err != nil:
+		b.WriteString(fmt.Sprintf("  [red]%s\n", tview.Escape(err.Error())))
+		return
+	case len(events) == 0:
+		b.WriteString("  <none>\n")
+		return
+	}
+
+	for _, event := range events {
+		color := "[white]"
+		if event.Type == corev1.EventTypeWarning {
+			color = "[yellow]"
+		}
+		count := ""
+		if event.Count > 1 {
+			count = fmt.Sprintf(" (x%d)", event.Count)
+		}
+		b.WriteString(fmt.Sprintf("  %s%s %s[white] %s%s: %s\n",
+			color, getAge(eventTime(event)), event.Type, tview.Escape(event.Reason), count, tview.Escape(event.Message)))
+	}
+}
+
+// objectEvents returns the events whose involved object is obj, sorted by last timestamp
+func (a *App) objectEvents(obj runtime.Object) ([]corev1.Event, error) {
+	if a.KubeClient == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Typed objects from informers have no kind set
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+			kind = gvks[0].Kind
+		}
+	}
+
+	selector := fields.Set{"involvedObject.name": object.GetName()}
+	if kind != "" {
+		selector["involvedObject.kind"] = kind
+	}
+	if object.GetNamespace() != "" {
+		selector["involvedObject.namespace"] = object.GetNamespace()
+	}
+	list, err := a.KubeClient.CoreV1().Events(object.GetNamespace()).List(a.getContext(), metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing events: %v", err)
+	}
+
+	// Field selectors are not applied by every client, and names are shared between kinds
+	var events []corev1.Event
+	for _, event := range list.Items {
+		involved := event.InvolvedObject
+		if involved.Name != object.GetName() || (kind != "" && involved.Kind != kind) ||
+			(involved.UID != "" && object.GetUID() != "" && involved.UID != object.GetUID()) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	return events, nil
+}
+
+// eventTime returns when an event last occurred
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/rivo/tview"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// lastAppliedAnnotation holds the configuration kubectl apply last applied to an object
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// diffContextLines is the number of unchanged lines shown around changes
+const diffContextLines = 3
+
+// Diff colors
+const (
+	diffAddedColor   = "[green]"
+	diffRemovedColor = "[red]"
+	diffHunkColor    = "[#5fafd7]"
+	diffHeaderColor  = "[::b]"
+)
+
+// diffView is the state of the diff shown in InfoView
+type diffView struct {
+	title   string
+	text    string // Unified diff
+	hunks   int
+	current int
+}
+
+// unifiedDiff returns the unified diff of two texts, or an empty string if they are equal
+func unifiedDiff(from, to, fromName, toName string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  diffContextLines,
+	})
+}
+
+// showDiff shows the diff of two texts in InfoView, where n/N move between its hunks
+func (a *App) showDiff(title, from, to, fromName, toName string) error {
+	text, err := unifiedDiff(from, to, fromName, toName)
+	if err != nil {
+		return fmt.Errorf("error computing diff: %v", err)
+	}
+
+	a.leaveManifest()
+	a.stopFollowing()
+	a.diff = &diffView{title: title, text: text}
+	a.renderDiff()
+	a.CurrentFocus = 3
+	a.UpdateFocus()
+	return nil
+}
+
+// renderDiff colors the diff shown in InfoView and scrolls to the current hunk. Every hunk
+// header is a region, so that it can be highlighted.
+func (a *App) renderDiff() {
+	d := a.diff
+	if d.text == "" {
+		a.InfoView.SetTitle(fmt.Sprintf(" Diff %s ", tview.Escape(d.title)))
+		a.InfoView.SetText(fmt.Sprintf("[green]No differences between %s", tview.Escape(d.title)))
+		return
+	}
+
+	var b strings.Builder
+	hunks := 0
+	for i, line := range strings.Split(strings.TrimSuffix(d.text, "\n"), "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		escaped := tview.Escape(line)
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			b.WriteString(diffHeaderColor + escaped + "[::-]")
+		case strings.HasPrefix(line, "@@"):
+			b.WriteString(fmt.Sprintf(`["hunk-%d"]%s%s[-][""]`, hunks, diffHunkColor, escaped))
+			hunks++
+		case strings.HasPrefix(line, "+"):
+			b.WriteString(diffAddedColor + escaped + "[-]")
+		case strings.HasPrefix(line, "-"):
+			b.WriteString(diffRemovedColor + escaped + "[-]")
+		default:
+			b.WriteString(escaped)
+		}
+	}
+	d.hunks = hunks
+	if d.current >= hunks {
+		d.current = 0
+	}
+
+	a.InfoView.SetTitle(fmt.Sprintf(" Diff %s [%d/%d] ", tview.Escape(d.title), min(d.current+1, hunks), hunks))
+	a.InfoView.SetText(b.String())
+	a.InfoView.Highlight(fmt.Sprintf("hunk-%d", d.current)).ScrollToHighlight()
+}
+
+// handleDiffKey handles the diff keys when InfoView is focused: n/N move between hunks and
+// Esc returns to the resource table
+func (a *App) handleDiffKey(event *tcell.EventKey) *tcell.EventKey {
+	d := a.diff
+	switch {
+	case event.Key() == tcell.KeyEscape:
+		a.leaveDiff()
+		a.InfoView.Clear()
+		a.CurrentFocus = 2
+		a.UpdateFocus()
+		return nil
+	case event.Key() == tcell.KeyRune && event.Rune() == 'n':
+		if d.hunks > 0 {
+			d.current = (d.current + 1) % d.hunks
+		}
+	case event.Key() == tcell.KeyRune && event.Rune() == 'N':
+		if d.hunks > 0 {
+			d.current = (d.current + d.hunks - 1) % d.hunks
+		}
+	default:
+		return event
+	}
+	a.renderDiff()
+	return nil
+}
+
+// leaveDiff forgets the diff shown in InfoView, before other details replace it
+func (a *App) leaveDiff() {
+	if a.diff == nil {
+		return
+	}
+	a.diff = nil
+	a.InfoView.Highlight()
+	a.InfoView.SetTitle(" Info ")
+}
+
+// diffLastApplied compares the object highlighted in the resource table with the
+// configuration kubectl apply last applied to it
+func (a *App) diffLastApplied() {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	if a.DynamicClient == nil {
+		a.showError("Dynamic client not initialized")
+		return
+	}
+	live, err := a.DynamicClient.Resource(kind.GVR).Namespace(accessor.GetNamespace()).Get(a.getContext(), accessor.GetName(), metav1.GetOptions{})
+	if err != nil {
+		a.showError(fmt.Sprintf("Error getting %s: %v", accessor.GetName(), err))
+		return
+	}
+
+	appliedText, liveText, err := lastAppliedDiffTexts(live)
+	if err != nil {
+		a.showError(fmt.Sprintf("Cannot diff %s: %v", accessor.GetName(), err))
+		return
+	}
+	title := fmt.Sprintf("%s/%s last-applied → live", kind.Type, accessor.GetName())
+	if err := a.showDiff(title, appliedText, liveText, "last-applied", "live"); err != nil {
+		a.showError(err.Error())
+	}
+}
+
+// lastAppliedDiffTexts returns the last applied configuration of an object and the object
+// itself as YAML, without the fields the server manages
+func lastAppliedDiffTexts(live *unstructured.Unstructured) (string, string, error) {
+	config, ok := live.GetAnnotations()[lastAppliedAnnotation]
+	if !ok {
+		return "", "", fmt.Errorf("it has no %s annotation; it was not created with kubectl apply", lastAppliedAnnotation)
+	}
+	var applied map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &applied); err != nil {
+		return "", "", fmt.Errorf("invalid %s annotation: %v", lastAppliedAnnotation, err)
+	}
+
+	obj := live.DeepCopy().Object
+	delete(obj, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink"} {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj, "metadata", "annotations", lastAppliedAnnotation)
+	if annotations, found, _ := unstructured.NestedMap(obj, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	}
+
+	appliedYAML, err := yaml.Marshal(applied)
+	if err != nil {
+		return "", "", err
+	}
+	liveYAML, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", "", err
+	}
+	return string(appliedYAML), string(liveYAML), nil
+}
+
+// diffRevisions compares the pod templates of two revisions of a workload
+func (a *App) diffRevisions(kind *ResourceKind, name string, from, to rolloutRevision) {
+	fromText, err := revisionTemplateText(from)
+	if err == nil {
+		var toText string
+		if toText, err = revisionTemplateText(to); err == nil {
+			title := fmt.Sprintf("%s/%s revision %d → %d", kind.Type, name, from.number, to.number)
+			err = a.showDiff(title, fromText, toText, fmt.Sprintf("revision %d", from.number), fmt.Sprintf("revision %d", to.number))
+		}
+	}
+	if err != nil {
+		a.showError(fmt.Sprintf("Error comparing revisions: %v", err))
+	}
+}
+
+// revisionTemplateText returns the pod template of a revision as YAML, without the
+// pod-template-hash label every replicaset of a deployment has its own value of
+func revisionTemplateText(revision rolloutRevision) (string, error) {
+	if revision.template == nil {
+		return "", fmt.Errorf("revision %d has no pod template", revision.number)
+	}
+	template := revision.template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	data, err := yaml.Marshal(struct {
+		Template *corev1.PodTemplateSpec `json:"template"`
+	}{template})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
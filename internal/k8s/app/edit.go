@@ -0,0 +1,199 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldManager identifies k8stui as the writer of the fields it changes
+const fieldManager = "k8stui"
+
+// editHeader explains the edit buffer, like the one kubectl edit writes
+const editHeader = `# Please edit the object below. Lines beginning with a '#' at the top will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this file will be
+# reopened with the relevant failures. Remove metadata.resourceVersion to server-side apply
+# the object instead of updating it; fields other managers own then have to be removed.
+#
+`
+
+// editSelected opens the object highlighted in the resource table in an editor
+func (a *App) editSelected() {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	edited, err := a.editResource(kind, accessor.GetNamespace(), accessor.GetName())
+	switch {
+	case err != nil:
+		a.showError(fmt.Sprintf("Error editing %s: %v", accessor.GetName(), err))
+	case edited:
+		a.showStatus(fmt.Sprintf("%s %s edited", kind.DisplayName, accessor.GetName()))
+	default:
+		a.showStatus("Edit cancelled, no changes made")
+	}
+}
+
+// editResource fetches the live object, lets the user edit it as YAML and applies the result.
+// The editor is reopened with the error at the top until the object is applied or the edit
+// is abandoned by saving an empty or unchanged file. It reports whether the object changed.
+func (a *App) editResource(kind *ResourceKind, namespace, name string) (bool, error) {
+	if a.DynamicClient == nil {
+		return false, fmt.Errorf("dynamic client not initialized")
+	}
+	client := a.DynamicClient.Resource(kind.GVR).Namespace(namespace)
+
+	live, err := client.Get(a.getContext(), name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error getting %s: %v", name, err)
+	}
+	unstructured.RemoveNestedField(live.Object, "metadata", "managedFields")
+	original, err := yaml.Marshal(live.Object)
+	if err != nil {
+		return false, fmt.Errorf("error encoding YAML: %v", err)
+	}
+
+	file, err := os.CreateTemp("", fmt.Sprintf("k8stui-%s-%s-*.yaml", kind.GVR.Resource, name))
+	if err != nil {
+		return false, fmt.Errorf("error creating temporary file: %v", err)
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	content := original
+	var applyErr error
+	for {
+		buffer := editHeader
+		if applyErr != nil {
+			buffer = editErrorHeader(applyErr) + buffer
+		}
+		if err := os.WriteFile(path, append([]byte(buffer), content...), 0600); err != nil {
+			return false, fmt.Errorf("error writing temporary file: %v", err)
+		}
+
+		if err := a.runEditor(path); err != nil {
+			return false, err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("error reading temporary file: %v", err)
+		}
+		content = stripEditHeader(data)
+		if len(bytes.TrimSpace(content)) == 0 || bytes.Equal(content, original) {
+			return false, nil
+		}
+
+		if applyErr = a.applyEdit(kind, namespace, name, content); applyErr == nil {
+			return true, nil
+		}
+	}
+}
+
+// applyEdit validates an edited manifest and writes it to the cluster. Manifests without a
+// resourceVersion are server-side applied, the others replace the object. The apply is not
+// forced, so that fields owned by other managers, such as replicas set by an autoscaler, are
+// reported as conflicts instead of being taken over.
+func (a *App) applyEdit(kind *ResourceKind, namespace, name string, content []byte) error {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(content, &obj.Object); err != nil {
+		return fmt.Errorf("invalid YAML: %v", err)
+	}
+	if obj.Object == nil {
+		return fmt.Errorf("the manifest is not an object")
+	}
+	if obj.GetName() != name {
+		return fmt.Errorf("metadata.name cannot be changed from %q", name)
+	}
+	if obj.GetNamespace() != namespace {
+		return fmt.Errorf("metadata.namespace cannot be changed from %q", namespace)
+	}
+	if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+		return fmt.Errorf("apiVersion and kind must be set")
+	}
+
+	client := a.DynamicClient.Resource(kind.GVR).Namespace(namespace)
+	if obj.GetResourceVersion() == "" {
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		_, err = client.Patch(a.getContext(), name, types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: fieldManager})
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("%v\nRemove the conflicting fields, which other managers own, or restore metadata.resourceVersion to replace the object", err)
+		}
+		return err
+	}
+
+	_, err := client.Update(a.getContext(), obj, metav1.UpdateOptions{FieldManager: fieldManager})
+	return err
+}
+
+// runEditor edits a file in $KUBE_EDITOR or $EDITOR, falling back to vi, with the terminal
+// UI suspended. Tests replace it through the editor field.
+func (a *App) runEditor(path string) error {
+	if a.editor != nil {
+		return a.editor(path)
+	}
+
+	editor := os.Getenv("KUBE_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := append(strings.Fields(editor), path)
+
+	var runErr error
+	suspended := a.App.Suspend(func() {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		runErr = cmd.Run()
+	})
+	if !suspended {
+		return fmt.Errorf("could not suspend the terminal UI")
+	}
+	if runErr != nil {
+		return fmt.Errorf("error running editor %s: %v", args[0], runErr)
+	}
+	return nil
+}
+
+// editErrorHeader formats an apply error as comment lines for the top of the edit buffer
+func editErrorHeader(err error) string {
+	var b strings.Builder
+	b.WriteString("# The edited object could not be saved:\n")
+	for _, line := range strings.Split(err.Error(), "\n") {
+		b.WriteString("# error: " + line + "\n")
+	}
+	b.WriteString("#\n")
+	return b.String()
+}
+
+// stripEditHeader removes the comment lines at the top of an edit buffer
+func stripEditHeader(data []byte) []byte {
+	for len(data) > 0 && data[0] == '#' {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil
+		}
+		data = data[i+1:]
+	}
+	return data
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// eventWarningColor draws Warning events apart from Normal ones
+const eventWarningColor = tcell.ColorOrangeRed
+
+// eventGroup is a set of repeated events about the same object, shown as one row
+type eventGroup struct {
+	latest   *corev1.Event
+	count    int32
+	lastSeen time.Time
+}
+
+// fillEventTable shows events in the resource table, collapsing repeated events into one row.
+// Filter terms match the involved object as kind/name, and s: terms match the reason.
+func (a *App) fillEventTable(kind *ResourceKind, objects []runtime.Object) {
+	groups := make(map[string]*eventGroup)
+	var order []string
+	for _, obj := range objects {
+		event, ok := obj.(*corev1.Event)
+		if !ok {
+			continue
+		}
+		involved := event.InvolvedObject
+		key := strings.Join([]string{event.Namespace, involved.Kind, involved.Name, event.Type, event.Reason, event.Message}, "\x00")
+
+		group, ok := groups[key]
+		if !ok {
+			group = &eventGroup{}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.count += eventCount(event)
+		if seen := eventTime(*event); group.latest == nil || seen.After(group.lastSeen) {
+			group.latest, group.lastSeen = event, seen
+		}
+	}
+
+	withNamespace := a.allNamespaces
+	columns := append([]string{"OBJECT"}, kind.Columns...)
+	if withNamespace {
+		columns = withNamespaceColumn(columns, "NAMESPACE")
+	}
+	a.ResourceList.SetColumns(columns)
+
+	a.ResourceList.ClearRows()
+	for _, key := range order {
+		group := groups[key]
+		event := group.latest
+		cells := []string{
+			strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name,
+			getAge(group.lastSeen),
+			event.Type,
+			event.Reason,
+			fmt.Sprint(group.count),
+			strings.ReplaceAll(event.Message, "\n", " "),
+		}
+		if withNamespace {
+			cells = withNamespaceColumn(cells, event.Namespace)
+		}
+
+		color := tcell.ColorDefault
+		if event.Type == corev1.EventTypeWarning {
+			color = eventWarningColor
+		}
+		ref := event.InvolvedObject
+		a.ResourceList.AddColoredRow(event.Namespace+"/"+event.Name, cells, event.Labels, color, func() {
+			a.showInvolvedObject(ref)
+		})
+	}
+	a.ResourceList.Render()
+}
+
+// eventCount returns how many times an event occurred
+func eventCount(event *corev1.Event) int32 {
+	switch {
+	case event.Series != nil && event.Series.Count > 0:
+		return event.Series.Count
+	case event.Count > 0:
+		return event.Count
+	}
+	return 1
+}
+
+// showInvolvedObject lists the kind of the object an event is about and shows its details
+func (a *App) showInvolvedObject(ref corev1.ObjectReference) {
+	kind, ok := a.kindForReference(ref)
+	if !ok {
+		a.showError(fmt.Sprintf("Cannot show %s objects", ref.Kind))
+		return
+	}
+	obj, err := a.fetchObject(kind, ref.Namespace, ref.Name)
+	if err != nil {
+		a.showError(fmt.Sprintf("Error getting %s %s: %v", ref.Kind, ref.Name, err))
+		return
+	}
+
+	if kind.Namespaced {
+		a.CurrentNs = ref.Namespace
+		a.SelectedNs = ref.Namespace
+		a.allNamespaces = false
+		if a.namespaceWatch != nil {
+			a.renderNamespaces(a.namespaceWatch.objects())
+		}
+	}
+	if err := a.LoadResources(kind.Type); err != nil {
+		a.showError(fmt.Sprintf("Error loading %s: %v", kind.DisplayName, err))
+		return
+	}
+	a.ResourceList.SelectKey(ref.Namespace + "/" + ref.Name)
+	a.selectResource(kind, obj)
+	a.CurrentFocus = 2
+	a.UpdateFocus()
+}
+
+// kindForReference returns the listed kind of the object an object reference points to
+func (a *App) kindForReference(ref corev1.ObjectReference) (*ResourceKind, bool) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, false
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(ref.Kind))
+
+	kinds := make([]*ResourceKind, 0, len(resourceKinds)+len(a.discoveredKinds))
+	kinds = append(append(kinds, resourceKinds...), a.discoveredKinds...)
+	for _, kind := range kinds {
+		if kind.GVR.Group == gvr.Group && kind.GVR.Resource == gvr.Resource {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// fetchObject gets an object of kind, as the typed object its informer would hold
+func (a *App) fetchObject(kind *ResourceKind, namespace, name string) (runtime.Object, error) {
+	if a.DynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+	live, err := a.DynamicClient.Resource(kind.GVR).Namespace(namespace).Get(a.getContext(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if kind.Dynamic {
+		return live, nil
+	}
+
+	typed, err := scheme.Scheme.New(live.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(live.Object, typed); err != nil {
+		return nil, fmt.Errorf("error converting object: %v", err)
+	}
+	return typed, nil
+}
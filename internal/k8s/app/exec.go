@@ -1,13 +1,25 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"golang.org/x/term"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
 )
 
+// execShells is the list of shells tried, in order, when opening a shell in a container
+var execShells = []string{"bash", "sh", "ash"}
+
+// execNotFoundMessages are how container runtimes report that the command of an exec could
+// not be started, such as runc's "exec: \"bash\": executable file not found in $PATH"
+var execNotFoundMessages = []string{"executable file not found", "no such file or directory"}
+
 // ExecInContainer executes a command in a container
 func (a *App) ExecInContainer(containerName string, command []string) error {
 	if a.KubeClient == nil || a.SelectedPod == "" || containerName == "" {
@@ -17,15 +29,16 @@ func (a *App) ExecInContainer(containerName string, command []string) error {
 	req := a.KubeClient.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(a.SelectedPod).
-		Namespace(a.CurrentNs).
+		Namespace(a.podNamespace()).
 		SubResource("exec")
 
+	// With a TTY the remote side merges stderr into stdout
 	req.VersionedParams(&corev1.PodExecOptions{
 		Container: containerName,
 		Command:   command,
 		Stdin:     true,
 		Stdout:    true,
-		Stderr:    true,
+		Stderr:    false,
 		TTY:       true,
 	}, scheme.ParameterCodec)
 
@@ -34,8 +47,121 @@ func (a *App) ExecInContainer(containerName string, command []string) error {
 		return fmt.Errorf("error creating SPDY executor: %v", err)
 	}
 
-	// TODO: Implement terminal emulation for interactive commands
-	// This is a placeholder for the actual terminal implementation
-	_ = exec // Silence unused variable warning for now
-	return fmt.Errorf("terminal emulation not yet implemented")
+	var streamErr error
+	suspended := a.App.Suspend(func() {
+		streamErr = a.streamTerminal(exec, fmt.Sprintf("%s/%s", a.SelectedPod, containerName))
+	})
+	if !suspended {
+		return fmt.Errorf("could not suspend the terminal UI")
+	}
+
+	return streamErr
+}
+
+// ExecShell opens an interactive shell in a container, falling back through
+// execShells when a shell is not present in the image
+func (a *App) ExecShell(containerName string) error {
+	exec := a.ExecInContainer
+	if a.exec != nil {
+		exec = a.exec
+	}
+	var err error
+	for _, shell := range execShells {
+		err = exec(containerName, []string{shell})
+		if !isCommandNotFound(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("no usable shell found in container %s: %v", containerName, err)
+}
+
+// streamTerminal hands the raw local terminal to the executor until the remote command exits
+func (a *App) streamTerminal(exec remotecommand.Executor, target string) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("stdin is not a terminal")
+	}
+
+	fmt.Fprintf(os.Stdout, "Connecting to %s...\r\n", target)
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("error putting terminal into raw mode: %v", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             os.Stdin,
+		Stdout:            os.Stdout,
+		Tty:               true,
+		TerminalSizeQueue: newTerminalSizeQueue(ctx, int(os.Stdout.Fd())),
+	})
+}
+
+// terminalSizeQueue reports local terminal size changes to the remote TTY
+type terminalSizeQueue struct {
+	sizes chan remotecommand.TerminalSize
+}
+
+// newTerminalSizeQueue starts monitoring the size of the terminal behind fd
+// until ctx is cancelled. The current size is always reported first.
+func newTerminalSizeQueue(ctx context.Context, fd int) *terminalSizeQueue {
+	q := &terminalSizeQueue{sizes: make(chan remotecommand.TerminalSize, 1)}
+
+	go func() {
+		defer close(q.sizes)
+
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+
+		var last remotecommand.TerminalSize
+		for {
+			if width, height, err := term.GetSize(fd); err == nil {
+				size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+				if size != last {
+					last = size
+					select {
+					case q.sizes <- size:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return q
+}
+
+// Next returns the next terminal size, or nil once monitoring has stopped
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// isCommandNotFound reports whether an exec error means the command could not be started.
+// Exit codes are not enough: an interactive shell exits with the status of its last command,
+// which is 127 after typing a missing command.
+func isCommandNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, message := range execNotFoundMessages {
+		if strings.Contains(err.Error(), message) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,221 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// listFilter narrows a list to the items matching every term of a filter expression.
+// Plain terms fuzzy-match the name, "l:" terms are label selectors and "s:" terms match the status.
+type listFilter struct {
+	text     string
+	names    []string
+	selector labels.Selector
+	statuses []string
+}
+
+// parseFilter parses a filter expression such as "web l:app=nginx s:CrashLoopBackOff"
+func parseFilter(text string) (*listFilter, error) {
+	f := &listFilter{text: text}
+	for _, term := range strings.Fields(text) {
+		switch {
+		case strings.HasPrefix(term, "l:"):
+			selector, err := labels.Parse(strings.TrimPrefix(term, "l:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid label selector: %v", err)
+			}
+			if f.selector != nil {
+				requirements, _ := selector.Requirements()
+				selector = f.selector.Add(requirements...)
+			}
+			f.selector = selector
+		case strings.HasPrefix(term, "s:"):
+			f.statuses = append(f.statuses, strings.ToLower(strings.TrimPrefix(term, "s:")))
+		default:
+			f.names = append(f.names, term)
+		}
+	}
+	return f, nil
+}
+
+// match reports whether an item matches the filter, along with the positions of the name
+// characters matched by fuzzy terms. A nil filter matches everything.
+func (f *listFilter) match(name string, itemLabels map[string]string, status string) ([]int, bool) {
+	if f == nil {
+		return nil, true
+	}
+	if f.selector != nil && !f.selector.Matches(labels.Set(itemLabels)) {
+		return nil, false
+	}
+	for _, s := range f.statuses {
+		if !strings.Contains(strings.ToLower(status), s) {
+			return nil, false
+		}
+	}
+
+	var matched []int
+	for _, term := range f.names {
+		positions, ok := fuzzyMatch(name, term)
+		if !ok {
+			return nil, false
+		}
+		matched = append(matched, positions...)
+	}
+	return matched, true
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in order in s, ignoring case,
+// and returns the byte offsets in s of the matched characters
+func fuzzyMatch(s, pattern string) ([]int, bool) {
+	var positions []int
+	p := []rune(strings.ToLower(pattern))
+	for i, r := range s {
+		if len(positions) == len(p) {
+			break
+		}
+		if unicode.ToLower(r) == p[len(positions)] {
+			positions = append(positions, i)
+		}
+	}
+	return positions, len(positions) == len(p)
+}
+
+// highlightMatches returns s as escaped tview text with the characters at the given byte
+// offsets highlighted
+func highlightMatches(s string, positions []int) string {
+	if len(positions) == 0 {
+		return tview.Escape(s)
+	}
+	highlighted := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		highlighted[p] = true
+	}
+
+	var b strings.Builder
+	for i, r := range s {
+		if highlighted[i] {
+			b.WriteString("[orange::b]" + tview.Escape(string(r)) + "[-::-]")
+		} else {
+			b.WriteString(tview.Escape(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// filterTitle returns a list title with the active filter appended
+func filterTitle(title string, f *listFilter) string {
+	if f == nil || f.text == "" {
+		return fmt.Sprintf(" %s ", title)
+	}
+	return fmt.Sprintf(" %s </%s> ", title, tview.Escape(f.text))
+}
+
+// showFilter opens the filter bar for the focused list, or the search bar when a manifest or
+// the logs are focused. The list narrows as the filter is typed; Enter keeps the filter and Esc clears it.
+func (a *App) showFilter() {
+	var current *listFilter
+	var apply func(*listFilter)
+	switch a.CurrentFocus {
+	case 0:
+		current, apply = a.nsFilter, a.setNamespaceFilter
+	case 2:
+		current, apply = a.ResourceList.filter, a.setResourceFilter
+	case 3:
+		if a.manifest != nil {
+			a.showInputBar("search: ", a.manifest.search, func(text string) bool {
+				a.searchManifest(text)
+				return true
+			}, func() { a.searchManifest("") })
+		}
+		return
+	case 4:
+		a.showInputBar("search: ", a.logView.search, func(text string) bool {
+			a.searchLogs(text)
+			return true
+		}, func() { a.searchLogs("") })
+		return
+	default:
+		return
+	}
+
+	text := ""
+	if current != nil {
+		text = current.text
+	}
+	a.showInputBar("/", text, func(text string) bool {
+		f, err := parseFilter(text)
+		if err != nil {
+			return false
+		}
+		apply(f)
+		return true
+	}, func() { apply(nil) })
+}
+
+// showInputBar shows a one-line input below the grid. changed is called as the text is edited
+// and reports whether the text is valid; cancelled is called when the input is left with Esc.
+func (a *App) showInputBar(label, text string, changed func(string) bool, cancelled func()) {
+	input := tview.NewInputField().SetLabel(label).SetText(text).SetFieldBackgroundColor(tcell.ColorDefault)
+	input.SetChangedFunc(func(text string) {
+		if changed(text) {
+			input.SetFieldTextColor(tcell.ColorWhite)
+		} else {
+			input.SetFieldTextColor(tcell.ColorRed)
+		}
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			cancelled()
+		}
+		a.mainFlex.RemoveItem(input)
+		a.UpdateFocus()
+	})
+
+	a.mainFlex.AddItem(input, 1, 0, true)
+	a.App.SetFocus(input)
+}
+
+// showPrompt opens an input bar asking for a value, which is submitted with Enter. Esc
+// cancels the prompt.
+func (a *App) showPrompt(label, text string, submit func(string)) {
+	input := tview.NewInputField().SetLabel(label).SetText(text).SetFieldBackgroundColor(tcell.ColorDefault)
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.mainFlex.RemoveItem(input)
+		a.UpdateFocus()
+		if key == tcell.KeyEnter {
+			submit(input.GetText())
+		}
+	})
+
+	a.mainFlex.AddItem(input, 1, 0, true)
+	a.App.SetFocus(input)
+}
+
+// setResourceFilter filters the resource table
+func (a *App) setResourceFilter(f *listFilter) {
+	a.ResourceList.SetFilter(f)
+	a.ResourceList.Render()
+	a.setResourceListTitle(a.resourceListTitle)
+}
+
+// setNamespaceFilter filters the namespace list
+func (a *App) setNamespaceFilter(f *listFilter) {
+	a.nsFilter = f
+	a.NsList.SetTitle(filterTitle("Namespaces", f))
+	if a.namespaceWatch != nil {
+		preserveSelection(a.NsList, func() {
+			a.renderNamespaces(a.namespaceWatch.objects())
+		})
+	}
+}
+
+// setResourceListTitle titles the resource table, showing its active filter
+func (a *App) setResourceListTitle(title string) {
+	a.resourceListTitle = title
+	a.ResourceList.SetTitle(filterTitle(title, a.ResourceList.filter))
+}
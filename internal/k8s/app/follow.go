@@ -0,0 +1,100 @@
+package app
+
+import (
+	"time"
+
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// followInterval is how often the progress of a followed object is redrawn without updates
+const followInterval = time.Second
+
+// followedObject is an object whose progress, such as a scale or a rollout, is shown in
+// InfoView as it is updated
+type followedObject struct {
+	kind      *ResourceKind
+	namespace string
+	name      string
+	obj       runtime.Object                // Last version seen
+	render    func(obj runtime.Object) bool // Shows the progress, reporting whether it is over
+	stop      chan struct{}
+}
+
+// follow shows the progress of an object in InfoView until render reports it is over or
+// another object is selected. It is rendered on every update of the resource table and every
+// followInterval, so that elapsed times and deadlines are current.
+func (a *App) follow(kind *ResourceKind, obj runtime.Object, title string, render func(obj runtime.Object) bool) {
+	a.stopFollowing()
+	a.leaveManifest()
+	a.leaveDiff()
+	name, namespace := "", ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		name, namespace = accessor.GetName(), accessor.GetNamespace()
+	}
+	f := &followedObject{kind: kind, namespace: namespace, name: name, obj: obj, render: render, stop: make(chan struct{})}
+	a.followed = f
+	a.InfoView.SetTitle(" " + tview.Escape(title) + " ")
+	if render(obj) {
+		a.followed = nil
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(followInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+				a.App.QueueUpdateDraw(func() {
+					if a.followed == f {
+						a.renderFollowed(f.obj)
+					}
+				})
+			}
+		}
+	}()
+}
+
+// updateFollowed renders the followed object again after the resource table was refreshed
+func (a *App) updateFollowed() {
+	f := a.followed
+	if f == nil || a.resourceWatch == nil || a.resourceWatch.gvr != f.kind.GVR {
+		return
+	}
+	key := f.name
+	if f.namespace != "" {
+		key = f.namespace + "/" + f.name
+	}
+	item, exists, err := a.resourceWatch.informer.GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return
+	}
+	if obj, ok := item.(runtime.Object); ok {
+		a.renderFollowed(obj)
+	}
+}
+
+// renderFollowed renders a version of the followed object, and stops following it once its
+// progress is over
+func (a *App) renderFollowed(obj runtime.Object) {
+	f := a.followed
+	f.obj = obj
+	if f.render(obj) {
+		close(f.stop)
+		a.followed = nil
+	}
+}
+
+// stopFollowing stops showing the progress of the followed object, if any
+func (a *App) stopFollowing() {
+	if a.followed == nil {
+		return
+	}
+	close(a.followed.stop)
+	a.followed = nil
+	a.InfoView.SetTitle(" Info ")
+}
@@ -0,0 +1,339 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// logFieldAliases are the keys structured loggers use for the time, level and message of a line
+var logFieldAliases = map[string][]string{
+	"time":  {"time", "ts", "timestamp", "@timestamp"},
+	"level": {"level", "lvl", "severity", "log.level"},
+	"msg":   {"msg", "message"},
+}
+
+// logLevelRanks orders level names from the most verbose to the most severe
+var logLevelRanks = map[string]int{
+	"trace": 0, "debug": 1, "info": 2, "notice": 2, "warn": 3, "warning": 3,
+	"error": 4, "err": 4, "fatal": 5, "panic": 5, "dpanic": 5, "critical": 5, "crit": 5,
+}
+
+// logLevelNames are the names of numeric levels, by rank
+var logLevelNames = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// logLevelColors are the colors of the levels, by rank
+var logLevelColors = []string{"[gray]", "[gray]", "[green]", "[yellow]", "[red]", "[fuchsia]"}
+
+const (
+	logTimeColor = "[darkcyan]"
+	logKeyColor  = "[#87afff]"
+)
+
+// jsonLog is a log line holding a JSON object
+type jsonLog struct {
+	prefix string // Text before the object, such as the timestamp added by the T option
+	fields map[string]interface{}
+}
+
+// parseJSONLog returns the fields of a JSON log line, or nil if the line is not a JSON object
+func parseJSONLog(text string) *jsonLog {
+	i := strings.IndexByte(text, '{')
+	if i < 0 || !strings.HasSuffix(strings.TrimSpace(text), "}") {
+		return nil
+	}
+	prefix := text[:i]
+	if prefix != "" && (!strings.HasSuffix(prefix, " ") || strings.ContainsAny(strings.TrimSpace(prefix), " \t")) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text[i:]))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil || decoder.More() {
+		return nil
+	}
+	return &jsonLog{prefix: prefix, fields: fields}
+}
+
+// field returns the key and value of a field. The time, level and msg names match any of
+// their aliases.
+func (l *jsonLog) field(name string) (string, interface{}, bool) {
+	keys := []string{name}
+	if group := logFieldGroup(name); group != "" {
+		keys = logFieldAliases[group]
+	}
+	for _, key := range keys {
+		if value, ok := l.fields[key]; ok {
+			return key, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// logFieldGroup returns time, level or msg if name is one of their aliases
+func logFieldGroup(name string) string {
+	for group, aliases := range logFieldAliases {
+		for _, alias := range aliases {
+			if strings.EqualFold(name, alias) {
+				return group
+			}
+		}
+	}
+	return ""
+}
+
+// spans renders the line as time level msg key=value. With fields, only those fields are
+// shown, in that order.
+func (l *jsonLog) spans(fields []string) []span {
+	var spans []span
+	if l.prefix != "" {
+		spans = append(spans, span{l.prefix, "[-]"})
+	}
+
+	// Fields are shown by group, which is empty for the fields other than time, level and msg
+	type shownField struct {
+		group, key string
+		value      interface{}
+	}
+	var shown []shownField
+	if len(fields) == 0 {
+		keys := make(map[string]bool)
+		for _, group := range []string{"time", "level", "msg"} {
+			if key, value, ok := l.field(group); ok {
+				keys[key] = true
+				shown = append(shown, shownField{group, key, value})
+			}
+		}
+		// The other fields, including further aliases of a group, are shown as they are named
+		var rest []string
+		for key := range l.fields {
+			if !keys[key] {
+				rest = append(rest, key)
+			}
+		}
+		sort.Strings(rest)
+		for _, key := range rest {
+			shown = append(shown, shownField{"", key, l.fields[key]})
+		}
+	} else {
+		for _, name := range fields {
+			if key, value, ok := l.field(name); ok {
+				shown = append(shown, shownField{logFieldGroup(name), key, value})
+			}
+		}
+	}
+
+	for i, f := range shown {
+		key, value := f.key, f.value
+		if i > 0 {
+			spans = append(spans, span{" ", "[-]"})
+		}
+		switch f.group {
+		case "time":
+			spans = append(spans, span{formatLogValue(value, false), logTimeColor})
+		case "level":
+			rank, known := logLevelRank(value)
+			text, color := strings.ToUpper(formatLogValue(value, false)), "[-]"
+			if known {
+				if _, numeric := value.(json.Number); numeric {
+					text = logLevelNames[rank]
+				}
+				color = logLevelColors[rank]
+			}
+			spans = append(spans, span{fmt.Sprintf("%-5s", text), color})
+		case "msg":
+			spans = append(spans, span{strings.ReplaceAll(formatLogValue(value, false), "\n", `\n`), "[white]"})
+		default:
+			spans = append(spans, span{key + "=", logKeyColor}, span{formatLogValue(value, true), "[-]"})
+		}
+	}
+	return spans
+}
+
+// formatLogValue formats a field value. Quoted strings are quoted when they contain spaces
+// or are empty.
+func formatLogValue(value interface{}, quoted bool) string {
+	switch v := value.(type) {
+	case string:
+		if quoted && (v == "" || strings.ContainsAny(v, " \t\n\"=")) {
+			return strconv.Quote(v)
+		}
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// logLevelRank returns the rank of a level name, or of a numeric level as used by bunyan and
+// pino (10 trace to 60 fatal)
+func logLevelRank(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		rank, ok := logLevelRanks[strings.ToLower(v)]
+		return rank, ok
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil || n < 10 {
+			return 0, false
+		}
+		return int(min(n/10-1, int64(len(logLevelNames)-1))), true
+	}
+	return 0, false
+}
+
+// fieldConditionRegexp parses a field condition such as level>=warn
+var fieldConditionRegexp = regexp.MustCompile(`^([^=!<>~]+?)(>=|<=|!=|==|=|>|<|~)(.*)$`)
+
+// fieldCondition is a condition on a field of JSON log lines
+type fieldCondition struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp // Value of a ~ condition
+}
+
+// parseFieldFilter parses space-separated field conditions, such as "level>=warn user=bob".
+// Comparison operators compare levels by severity, numbers numerically and other values as
+// text; ~ matches a regular expression.
+func parseFieldFilter(expr string) ([]fieldCondition, error) {
+	var conditions []fieldCondition
+	for _, term := range strings.Fields(expr) {
+		m := fieldConditionRegexp.FindStringSubmatch(term)
+		if m == nil {
+			return nil, fmt.Errorf("invalid field condition %q", term)
+		}
+		c := fieldCondition{field: m[1], op: m[2], value: m[3]}
+		if c.op == "==" {
+			c.op = "="
+		}
+		if c.op == "~" {
+			re, err := regexp.Compile(c.value)
+			if err != nil {
+				return nil, err
+			}
+			c.re = re
+		} else if logFieldGroup(c.field) == "level" && c.op != "=" && c.op != "!=" {
+			if _, ok := logLevelRanks[strings.ToLower(c.value)]; !ok {
+				return nil, fmt.Errorf("unknown level %q", c.value)
+			}
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// match reports whether a line meets the condition. A missing field only meets != conditions.
+func (c fieldCondition) match(l *jsonLog) bool {
+	_, value, ok := l.field(c.field)
+	if !ok {
+		return c.op == "!="
+	}
+	text := formatLogValue(value, false)
+	if c.re != nil {
+		return c.re.MatchString(text)
+	}
+
+	var cmp int
+	if rank, ok := logLevelRank(value); ok && logFieldGroup(c.field) == "level" {
+		want, known := logLevelRanks[strings.ToLower(c.value)]
+		if !known {
+			return compareResult(c.op, strings.Compare(strings.ToLower(text), strings.ToLower(c.value)))
+		}
+		cmp = rank - want
+	} else if x, err := strconv.ParseFloat(text, 64); err == nil {
+		y, err := strconv.ParseFloat(c.value, 64)
+		if err != nil {
+			return compareResult(c.op, strings.Compare(text, c.value))
+		}
+		cmp = compareFloats(x, y)
+	} else {
+		cmp = strings.Compare(strings.ToLower(text), strings.ToLower(c.value))
+	}
+	return compareResult(c.op, cmp)
+}
+
+// compareFloats returns -1, 0 or 1 as x is less than, equal to or greater than y
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// compareResult applies a comparison operator to the result of a comparison
+func compareResult(op string, cmp int) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// setLogFields sets the fields shown of JSON log lines, separated by commas or spaces. An
+// empty list shows every field.
+func (a *App) setLogFields(list string) {
+	a.logView.fields = strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+	a.renderLogOptions()
+	a.renderLogs()
+}
+
+// setLogFieldFilter sets the field conditions JSON log lines must meet. Other lines are
+// hidden while conditions are set.
+func (a *App) setLogFieldFilter(expr string) error {
+	conditions, err := parseFieldFilter(expr)
+	if err != nil {
+		return err
+	}
+	a.logView.where = conditions
+	a.logView.whereText = strings.Join(strings.Fields(expr), " ")
+	a.renderLogs()
+	return nil
+}
+
+// toggleJSONLogs switches between formatted and raw JSON log lines
+func (a *App) toggleJSONLogs() {
+	a.logView.raw = !a.logView.raw
+	a.renderLogOptions()
+	a.renderLogs()
+}
+
+// showLogFields opens an input bar to pick the fields shown of JSON log lines
+func (a *App) showLogFields() {
+	a.showInputBar("fields: ", strings.Join(a.logView.fields, ","), func(text string) bool {
+		a.setLogFields(text)
+		return true
+	}, func() { a.setLogFields("") })
+}
+
+// showLogFieldFilter opens an input bar to edit the field conditions of the log pane
+func (a *App) showLogFieldFilter() {
+	a.showInputBar("where: ", a.logView.whereText, func(text string) bool {
+		return a.setLogFieldFilter(text) == nil
+	}, func() { _ = a.setLogFieldFilter("") })
+}
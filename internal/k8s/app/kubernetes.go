@@ -3,40 +3,53 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	rest "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 // initKubeClient initializes the Kubernetes client
 func (a *App) initKubeClient() error {
-	var kubeconfig string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = filepath.Join(home, ".kube", "config")
+	// Default loading rules honor $KUBECONFIG (merging every listed file) and ~/.kube/config
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if a.options.Kubeconfig != "" {
+		loadingRules.ExplicitPath = a.options.Kubeconfig
 	}
+	a.loadingRules = loadingRules
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: a.options.Context})
 
 	// Try to use the real Kubernetes config first
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := clientConfig.ClientConfig()
 	if err == nil {
 		// Successfully got real config
-		clientset, err := kubernetes.NewForConfig(config)
-		if err != nil {
-			return fmt.Errorf("error creating kubernetes client: %v", err)
+		if err := a.setKubeClient(config); err != nil {
+			return err
 		}
-		a.KubeClient = clientset
-		a.RestConfig = config
+		a.CurrentContext = a.options.Context
+		if rawConfig, err := clientConfig.RawConfig(); err == nil && a.CurrentContext == "" {
+			a.CurrentContext = rawConfig.CurrentContext
+		}
+		a.applyStartupNamespace(clientConfig)
 		return nil
 	}
 
+	// An explicitly requested kubeconfig or context must not silently fall back
+	if a.options.Kubeconfig != "" || a.options.Context != "" {
+		return fmt.Errorf("error loading kubeconfig: %v", err)
+	}
+
 	// Fall back to fake client for demonstration/testing
 	fmt.Fprintf(os.Stderr, "Warning: Using fake Kubernetes client for demonstration\n")
 	
@@ -109,74 +122,128 @@ func (a *App) initKubeClient() error {
 	)
 
 	a.KubeClient = fakeClient
+	a.DynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 	// Use a mock config for fake client
 	a.RestConfig = &rest.Config{Host: "fake-cluster"}
+	a.applyStartupNamespace(nil)
 	return nil
 }
 
-// LoadNamespaces loads the list of namespaces from the Kubernetes cluster
-func (a *App) LoadNamespaces() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
+// setKubeClient builds the Kubernetes clients for the given REST config
+func (a *App) setKubeClient(config *rest.Config) error {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &readOnlyTransport{app: a, next: rt}
+	})
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error creating kubernetes client: %v", err)
 	}
-
-	namespaces, err := a.KubeClient.CoreV1().Namespaces().List(a.getContext(), metav1.ListOptions{})
+	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		return fmt.Errorf("error listing namespaces: %v", err)
+		return fmt.Errorf("error creating dynamic client: %v", err)
 	}
+	a.KubeClient = clientset
+	a.DynamicClient = dynamicClient
+	a.RestConfig = config
+	a.discoveredKinds = nil
+	return nil
+}
 
-	a.NsList.Clear()
-	a.ResourceList.Clear()
-	for _, ns := range namespaces.Items {
-		a.NsList.AddItem(ns.Name, "", 0, func() {
-			a.CurrentNs = ns.Name
-			a.SelectedNs = ns.Name
-			a.ResourceList.Clear()
-			a.InfoView.Clear()
-			a.LoadResources(ResourceTypePod)
-		})
+// applyStartupNamespace selects the namespace requested on the command line. When only a
+// namespaced resource type was requested, the namespace of the kubeconfig context is used.
+func (a *App) applyStartupNamespace(clientConfig clientcmd.ClientConfig) {
+	ns := a.options.Namespace
+	if ns == "" && a.options.Resource != "" && IsNamespacedResourceType(a.options.Resource) {
+		ns = metav1.NamespaceDefault
+		if clientConfig != nil {
+			if contextNs, _, err := clientConfig.Namespace(); err == nil && contextNs != "" {
+				ns = contextNs
+			}
+		}
 	}
 
-	return nil
+	a.CurrentNs = ns
+	a.SelectedNs = ns
 }
 
-// LoadPods loads the list of pods in the current namespace
-func (a *App) LoadPods() error {
+// LoadNamespaces loads the list of namespaces from the Kubernetes cluster
+func (a *App) LoadNamespaces() error {
 	if a.KubeClient == nil {
 		return fmt.Errorf("kubernetes client not initialized")
 	}
-	if a.CurrentNs == "" {
-		return fmt.Errorf("no namespace selected")
-	}
 
-	// Hide logs window when displaying pods
-	a.showLogsWindow(false)
+	a.ResourceList.Clear()
+	a.viewingContainers = false
 
-	pods, err := a.KubeClient.CoreV1().Pods(a.CurrentNs).List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing pods: %v", err)
-	}
+	w, err := a.startWatch(a.namespaceWatch, corev1.SchemeGroupVersion.WithResource("namespaces"), metav1.NamespaceAll, false, func(fill func()) { preserveSelection(a.NsList, fill) }, func(objects []runtime.Object) func() {
+		return func() { a.renderNamespaces(objects) }
+	})
+	a.namespaceWatch = w
 
-	a.ResourceList.Clear()
-	for _, pod := range pods.Items {
-		podName := pod.Name // Capture the pod name in closure
-		status := "Running"
-		if pod.Status.Phase != corev1.PodRunning {
-			status = string(pod.Status.Phase)
+	return err
+}
+
+// allNamespacesItem is the NsList entry listing the resources of every namespace
+const allNamespacesItem = "All namespaces"
+
+// renderNamespaces fills NsList with the namespaces matching its filter, highlighting CurrentNs
+func (a *App) renderNamespaces(objects []runtime.Object) {
+	a.NsList.Clear()
+	if positions, ok := a.nsFilter.match(allNamespacesItem, nil, ""); ok {
+		a.NsList.AddItem("[::b]"+highlightMatches(allNamespacesItem, positions), "", 0, func() {
+			a.selectNamespace("", true)
+		})
+		if a.allNamespaces {
+			a.NsList.SetCurrentItem(0)
 		}
-		
-		a.ResourceList.AddItem(pod.Name, status, 0, func() {
-			a.SelectedPod = podName
-			a.LoadContainers(podName)
-			a.showPodStatus(&pod)
-			// Update responsive layout after selection
-			if a.grid != nil {
-				a.updateGridLayout(a.grid)
-			}
+	}
+	for _, obj := range objects {
+		ns := obj.(*corev1.Namespace)
+		positions, ok := a.nsFilter.match(ns.Name, ns.Labels, string(ns.Status.Phase))
+		if !ok {
+			continue
+		}
+		a.NsList.AddItem(highlightMatches(ns.Name, positions), "", 0, func() {
+			a.selectNamespace(ns.Name, false)
 		})
+		if ns.Name == a.CurrentNs && !a.allNamespaces {
+			a.NsList.SetCurrentItem(a.NsList.GetItemCount() - 1)
+		}
 	}
+}
 
-	return nil
+// selectNamespace lists the resources of a namespace, or of every namespace
+func (a *App) selectNamespace(namespace string, all bool) {
+	a.CurrentNs = namespace
+	a.SelectedNs = namespace
+	a.allNamespaces = all
+	a.ResourceList.Clear()
+	a.InfoView.Clear()
+	resourceType := a.SelectedResourceType
+	if kind, ok := a.lookupKind(resourceType); !ok || (!kind.Namespaced && !all) {
+		resourceType = ResourceTypePod
+	}
+	a.LoadResources(resourceType)
+}
+
+// podNamespace returns the namespace of the selected pod
+func (a *App) podNamespace() string {
+	if a.SelectedPodNs != "" {
+		return a.SelectedPodNs
+	}
+	return a.CurrentNs
+}
+
+// LoadPods loads the list of pods in the current namespace
+func (a *App) LoadPods() error {
+	return a.LoadResources(ResourceTypePod)
+}
+
+// selectPod shows the containers and status of a pod selected in the resource list
+func (a *App) selectPod(pod *corev1.Pod) error {
+	a.SelectedPod = pod.Name
+	a.SelectedPodNs = pod.Namespace
+	return a.LoadContainers(pod.Name)
 }
 
 // LoadContainers loads the containers for a given pod
@@ -184,22 +251,39 @@ func (a *App) LoadContainers(podName string) error {
 	if a.KubeClient == nil {
 		return fmt.Errorf("kubernetes client not initialized")
 	}
-	if a.CurrentNs == "" {
+	namespace := a.podNamespace()
+	if namespace == "" {
 		return fmt.Errorf("no namespace selected")
 	}
 
-	pod, err := a.KubeClient.CoreV1().Pods(a.CurrentNs).Get(a.getContext(), podName, metav1.GetOptions{})
+	pod, err := a.KubeClient.CoreV1().Pods(namespace).Get(a.getContext(), podName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("error getting pod: %v", err)
 	}
 
+	// The pod list is being replaced, so stop watching it
+	a.stopResourceWatch()
+
 	// Show logs window when displaying containers
 	a.showLogsWindow(true)
 
-	a.ResourceList.Clear()
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses[status.Name] = status
+	}
+
+	a.viewingContainers = true
+	a.leaveManifest()
+	a.leaveDiff()
+	a.ResourceList.SetFilter(nil)
+	a.setResourceListTitle(fmt.Sprintf("Containers of %s", pod.Name))
+	a.ResourceList.SetColumns([]string{"NAME", "IMAGE", "READY", "STATE", "RESTARTS"})
+	a.ResourceList.ClearRows()
 	for _, container := range pod.Spec.Containers {
 		containerName := container.Name // Capture the container name in closure
-		a.ResourceList.AddItem(container.Name, "", 0, func() {
+		status := statuses[container.Name]
+		cells := []string{container.Name, container.Image, fmt.Sprint(status.Ready), containerState(status.State), fmt.Sprint(status.RestartCount)}
+		a.ResourceList.AddRow(container.Name, cells, nil, func() {
 			// Automatically show logs when container is selected
 			a.ShowContainerLogs(containerName)
 			// Update responsive layout after selection
@@ -208,6 +292,7 @@ func (a *App) LoadContainers(podName string) error {
 			}
 		})
 	}
+	a.ResourceList.Render()
 
 	return a.showPodStatus(pod)
 }
@@ -219,13 +304,7 @@ func (a *App) showPodStatus(pod *corev1.Pod) error {
 	}
 
 	// Stop any existing log stream
-	if a.logStream != nil {
-		a.logStream.Close()
-	}
-	if a.logStopChan != nil {
-		close(a.logStopChan)
-	}
-	a.logStopChan = make(chan struct{})
+	a.stopLogStream()
 
 	// Format pod status
 	var status strings.Builder
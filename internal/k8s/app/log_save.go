@@ -0,0 +1,198 @@
+package app
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// logFileTimeFormat names saved log files after the time they were saved
+const logFileTimeFormat = "20060102-150405.000"
+
+// logFile is where a log is saved. It is set up on the UI goroutine, since the full log of
+// a container is saved in the background while the log options may change.
+type logFile struct {
+	dir  string // <log dir>/<context>/<namespace>/<pod>/<container>
+	gzip bool
+}
+
+// saveLogs saves the logs shown in LogsView: the buffer as received, or with full set, the
+// whole log of the container fetched again. The file and its size are shown in the status bar.
+func (a *App) saveLogs(full bool) {
+	if full {
+		if a.aggregator != nil || a.SelectedCont == "" {
+			a.showError("The full log can only be fetched for a single container")
+			return
+		}
+		a.showStatus("Fetching the full log...")
+		save := a.saveContainerLog(a.podNamespace(), a.SelectedPod, a.SelectedCont)
+		go func() {
+			path, size, err := save()
+			a.App.QueueUpdateDraw(func() { a.showSavedLogs(path, size, err) })
+		}()
+		return
+	}
+
+	if a.logBuffer == nil {
+		a.showError("No logs to save")
+		return
+	}
+	path, size, err := a.saveLogBuffer()
+	a.showSavedLogs(path, size, err)
+}
+
+// showSavedLogs reports the outcome of saving logs
+func (a *App) showSavedLogs(path string, size int64, err error) {
+	if err != nil {
+		a.showError(fmt.Sprintf("Error saving logs: %v", err))
+		return
+	}
+	a.showStatus(fmt.Sprintf("Logs saved to %s (%s)", path, formatBytes(size)))
+}
+
+// saveLogBuffer writes every line of the log buffer, unfiltered, to a new log file
+func (a *App) saveLogBuffer() (string, int64, error) {
+	lines, partial, _ := a.logBuffer.snapshot()
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line.String() + "\n")
+	}
+	if partial != "" {
+		b.WriteString(partial + "\n")
+	}
+
+	pod, container := a.SelectedPod, a.SelectedCont
+	namespace := a.podNamespace()
+	if a.aggregator != nil {
+		// Aggregated logs are named after their workload, such as deployment-web/all
+		pod, container, namespace = strings.ReplaceAll(a.aggregator.source, "/", "-"), "all", a.aggregator.namespace
+	}
+	return a.newLogFile(namespace, pod, container).write(strings.NewReader(b.String()))
+}
+
+// saveContainerLog returns a function fetching the whole log of a container, without
+// following it, into a new log file. Everything it needs from the app is read on the calling
+// goroutine, so that the function can run in the background.
+func (a *App) saveContainerLog(namespace, pod, container string) func() (string, int64, error) {
+	client, file := a.KubeClient, a.newLogFile(namespace, pod, container)
+	opts := &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   a.logOptions.previous,
+		Timestamps: a.logOptions.timestamps,
+	}
+	return func() (string, int64, error) {
+		if client == nil {
+			return "", 0, fmt.Errorf("kubernetes client not initialized")
+		}
+		stream, err := client.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(context.Background())
+		if err != nil {
+			return "", 0, fmt.Errorf("error fetching log: %v", err)
+		}
+		defer stream.Close()
+		return file.write(stream)
+	}
+}
+
+// newLogFile returns where the log of a container is saved with the current options
+func (a *App) newLogFile(namespace, pod, container string) logFile {
+	kubeContext := a.CurrentContext
+	if kubeContext == "" {
+		kubeContext = "default"
+	}
+	return logFile{
+		dir:  filepath.Join(a.logDir, pathComponent(kubeContext), pathComponent(namespace), pathComponent(pod), pathComponent(container)),
+		gzip: a.logOptions.gzip,
+	}
+}
+
+// write copies a log into a new file of the directory named after the current time, gzipped
+// when enabled. A numbered suffix keeps saves within the same millisecond apart. It returns
+// the path and size of the file.
+func (f logFile) write(log io.Reader) (string, int64, error) {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return "", 0, fmt.Errorf("error creating log directory: %v", err)
+	}
+
+	stamp := time.Now().Format(logFileTimeFormat)
+	var path string
+	var file *os.File
+	for i := 0; ; i++ {
+		name := stamp
+		if i > 0 {
+			name += fmt.Sprintf("-%d", i)
+		}
+		name += ".log"
+		if f.gzip {
+			name += ".gz"
+		}
+		path = filepath.Join(f.dir, name)
+		var err error
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", 0, fmt.Errorf("error creating log file: %v", err)
+		}
+	}
+	defer file.Close()
+
+	var w io.Writer = file
+	var zw *gzip.Writer
+	if f.gzip {
+		zw = gzip.NewWriter(file)
+		w = zw
+	}
+	if _, err := io.Copy(w, log); err != nil {
+		return "", 0, fmt.Errorf("error writing log file: %v", err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return "", 0, fmt.Errorf("error writing log file: %v", err)
+		}
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), file.Close()
+}
+
+// pathComponent makes a name safe to use as a single path component. Context names such as
+// EKS ARNs contain slashes and colons.
+func pathComponent(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', 0:
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// formatBytes formats a size in bytes with a binary unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -2,11 +2,56 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 	corev1 "k8s.io/api/core/v1"
 )
 
+// logTailChoices are the numbers of lines a log stream can start with; -1 fetches the whole log
+var logTailChoices = []int64{100, 1000, -1}
+
+// logSinceChoices are the windows a log stream can be limited to; 0 does not limit it
+var logSinceChoices = []time.Duration{0, 5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// logOptions are the options of the log stream shown in LogsView, changed from its options bar
+type logOptions struct {
+	follow     bool
+	previous   bool // Logs of the previous, terminated instance of the container
+	timestamps bool
+	tail       int  // Index in logTailChoices
+	since      int  // Index in logSinceChoices
+	gzip       bool // Compress saved logs
+}
+
+// defaultLogOptions follows the last 100 lines of a container log
+func defaultLogOptions() logOptions {
+	return logOptions{follow: true}
+}
+
+// podLogOptions returns the options of a log request for a container
+func (o logOptions) podLogOptions(containerName string) *corev1.PodLogOptions {
+	opts := &corev1.PodLogOptions{
+		Container:  containerName,
+		Follow:     o.follow,
+		Previous:   o.previous,
+		Timestamps: o.timestamps,
+	}
+	if tail := logTailChoices[o.tail]; tail >= 0 {
+		opts.TailLines = &tail
+	}
+	if since := logSinceChoices[o.since]; since > 0 {
+		seconds := int64(since.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+	return opts
+}
+
 // ShowContainerLogs displays logs for a container
 func (a *App) ShowContainerLogs(containerName string) error {
 	if a.KubeClient == nil || a.SelectedPod == "" || containerName == "" {
@@ -18,56 +63,50 @@ func (a *App) ShowContainerLogs(containerName string) error {
 	a.LogsView.SetText("[yellow]Loading logs...")
 	
 	// Stop any existing log stream
-	if a.logStream != nil {
-		a.logStream.Close()
-	}
-	if a.logStopChan != nil {
-		close(a.logStopChan)
-	}
-	a.logStopChan = make(chan struct{})
+	a.stopLogStream()
 
 	// Create log stream request
-	podLogOpts := &corev1.PodLogOptions{
-		Container: containerName,
-		Follow:    true,
-		TailLines: func() *int64 { i := int64(100); return &i }(), // Show last 100 lines
-	}
+	podLogOpts := a.logOptions.podLogOptions(containerName)
 
-	req := a.KubeClient.CoreV1().Pods(a.CurrentNs).GetLogs(a.SelectedPod, podLogOpts)
+	req := a.KubeClient.CoreV1().Pods(a.podNamespace()).GetLogs(a.SelectedPod, podLogOpts)
 	stream, err := req.Stream(context.Background())
 	if err != nil {
-		a.LogsView.SetText(fmt.Sprintf("[red]Error opening log stream: %v", err))
+		a.LogsView.SetText(fmt.Sprintf("[red]Error opening log stream: %s", tview.Escape(err.Error())))
 		return fmt.Errorf("error opening log stream: %v", err)
 	}
 
 	a.logStream = stream
 
+	// Every stream gets its own buffer, so a stream being stopped cannot write to the next one
+	buffer := &logBuffer{}
+	a.logBuffer = buffer
+	a.logView.paused, a.logView.pausedLines = false, nil
+	stop := a.logStopChan
+
 	// Start a goroutine to read logs
 	go func() {
 		defer stream.Close()
 		buf := make([]byte, 4096)
-		firstRead := true
 		for {
 			select {
-			case <-a.logStopChan:
+			case <-stop:
 				return
 			default:
 				n, err := stream.Read(buf)
 				if n > 0 {
-					a.App.QueueUpdateDraw(func() {
-						if firstRead {
-							// Clear loading message on first log data
-							a.LogsView.Clear()
-							firstRead = false
-						}
-						fmt.Fprintf(a.LogsView, "%s", string(buf[:n]))
-						a.LogsView.ScrollToEnd()
-					})
+					buffer.write(string(buf[:n]))
+					a.queueLogsReceived(buffer)
 				}
 				if err != nil {
-					if err.Error() != "EOF" {
+					if !errors.Is(err, io.EOF) {
 						a.App.QueueUpdateDraw(func() {
-							a.LogsView.SetText(fmt.Sprintf("[red]Error reading logs: %v", err))
+							// Closing the stream to restart it fails its read, which must not
+							// replace the output of the next stream
+							select {
+							case <-stop:
+							default:
+								a.LogsView.SetText(fmt.Sprintf("[red]Error reading logs: %s", tview.Escape(err.Error())))
+							}
 						})
 					}
 					return
@@ -77,9 +116,141 @@ func (a *App) ShowContainerLogs(containerName string) error {
 	}()
 
 	a.SelectedCont = containerName
-	a.LogsView.SetTitle(fmt.Sprintf(" Logs: %s ", containerName))
+	a.logSource = containerName
+	a.updateLogsTitle()
 
 	return nil
 }
 
 
+
+// restartLogs reopens the logs shown in LogsView with the current log options. Errors are
+// shown in LogsView.
+func (a *App) restartLogs() {
+	switch {
+	case a.aggregator != nil:
+		_ = a.startAggregatedLogs(a.aggregator.source, a.aggregator.namespace, a.aggregator.selector)
+	case a.SelectedCont != "":
+		_ = a.ShowContainerLogs(a.SelectedCont)
+	}
+}
+
+// stopLogStream stops the running log stream, if any
+func (a *App) stopLogStream() {
+	if a.aggregator != nil {
+		a.aggregator.stop()
+		a.aggregator = nil
+	}
+	if a.logStream != nil {
+		a.logStream.Close()
+		a.logStream = nil
+	}
+	if a.logStopChan != nil {
+		close(a.logStopChan)
+	}
+	a.logStopChan = make(chan struct{})
+}
+
+// renderLogOptions shows the log options and the keys that change them in the options bar
+func (a *App) renderLogOptions() {
+	o := a.logOptions
+	tail := "all"
+	if lines := logTailChoices[o.tail]; lines >= 0 {
+		tail = fmt.Sprint(lines)
+	}
+	since := "all"
+	if window := logSinceChoices[o.since]; window > 0 {
+		since = strings.TrimSuffix(strings.TrimSuffix(window.String(), "0s"), "0m")
+	}
+
+	fields := "all"
+	if len(a.logView.fields) > 0 {
+		fields = tview.Escape(strings.Join(a.logView.fields, ","))
+	}
+
+	a.logOptionsBar.SetText(strings.Join([]string{
+		logOptionText("F", "follow", onOff(o.follow)),
+		logOptionText("P", "previous", onOff(o.previous)),
+		logOptionText("T", "timestamps", onOff(o.timestamps)),
+		logOptionText("L", "tail", tail),
+		logOptionText("S", "since", since),
+		logOptionText("Z", "gzip", onOff(o.gzip)),
+		logOptionText("J", "json", onOff(!a.logView.raw)),
+		logOptionText("K", "fields", fields),
+	}, " "))
+}
+
+// logOptionText formats an option of the options bar with its key
+func logOptionText(key, name, value string) string {
+	return fmt.Sprintf("[::b]%s[::-] %s:[yellow]%s[-]", key, name, value)
+}
+
+// onOff formats a toggle
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// handleLogsKey handles the log pane keys when LogsView is focused. Changing a log option
+// restarts the stream.
+func (a *App) handleLogsKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+
+	o := &a.logOptions
+	switch event.Rune() {
+	case 'f', 'F':
+		o.follow = !o.follow
+	case 'p', 'P':
+		o.previous = !o.previous
+	case 't', 'T':
+		o.timestamps = !o.timestamps
+	case 'l', 'L':
+		o.tail = (o.tail + 1) % len(logTailChoices)
+	case 's', 'S':
+		o.since = (o.since + 1) % len(logSinceChoices)
+	case ' ':
+		a.togglePauseLogs()
+		return nil
+	case 'n':
+		a.nextLogMatch(true)
+		return nil
+	case 'N':
+		a.nextLogMatch(false)
+		return nil
+	case 'i', 'I':
+		a.showLogFilter(true)
+		return nil
+	case 'e', 'E':
+		a.showLogFilter(false)
+		return nil
+	case 'w':
+		a.saveLogs(false)
+		return nil
+	case 'W':
+		a.saveLogs(true)
+		return nil
+	case 'j', 'J':
+		a.toggleJSONLogs()
+		return nil
+	case 'k', 'K':
+		a.showLogFields()
+		return nil
+	case 'v', 'V':
+		a.showLogFieldFilter()
+		return nil
+	case 'z', 'Z':
+		o.gzip = !o.gzip
+		a.renderLogOptions()
+		return nil
+	default:
+		return event
+	}
+
+	a.renderLogOptions()
+	a.restartLogs()
+	return nil
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rivo/tview"
+)
+
+// maxLogLines is the number of log lines kept per stream; older lines are dropped
+const maxLogLines = 10000
+
+// logLine is a line of a log stream
+type logLine struct {
+	source string // pod/container tag of aggregated logs, empty for a single container
+	color  string // Color tag of the source
+	text   string
+	json   *jsonLog // Fields of the line if it is a JSON object
+}
+
+// logBuffer holds the lines of one or more log streams. It is written by the stream readers
+// and read by the UI, so it is safe for concurrent use.
+type logBuffer struct {
+	mu      sync.Mutex
+	lines   []logLine
+	partial string // Text after the last newline of a single stream
+	total   int    // Lines received, including the dropped ones
+
+	pending atomic.Bool // A redraw of the lines is queued
+}
+
+// write appends a chunk of a log stream, which may end in the middle of a line
+func (b *logBuffer) write(chunk string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parts := strings.Split(b.partial+chunk, "\n")
+	b.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		b.append(logLine{text: strings.TrimSuffix(line, "\r")})
+	}
+}
+
+// add appends a complete line
+func (b *logBuffer) add(line logLine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.append(line)
+}
+
+// append appends a line, dropping the oldest ones beyond maxLogLines. b.mu must be held.
+func (b *logBuffer) append(line logLine) {
+	line.json = parseJSONLog(line.text)
+	b.lines = append(b.lines, line)
+	b.total++
+	if len(b.lines) > maxLogLines {
+		b.lines = append([]logLine(nil), b.lines[len(b.lines)-maxLogLines:]...)
+	}
+}
+
+// snapshot returns the complete lines kept, the unterminated last line and the number of
+// lines received
+func (b *logBuffer) snapshot() ([]logLine, string, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]logLine(nil), b.lines...), b.partial, b.total
+}
+
+// String returns the line as written, prefixed with its source
+func (l logLine) String() string {
+	if l.source == "" {
+		return l.text
+	}
+	return l.source + " " + l.text
+}
+
+// logView is the state of the log pane: what is shown of the buffer and how
+type logView struct {
+	paused      bool
+	pausedLines []logLine // Lines shown when the pane was paused
+	pausedTotal int       // Lines received when the pane was paused
+	search      string    // Highlighted text, case-insensitive
+	matches     int
+	current     int
+	include     *regexp.Regexp // Only lines matching are shown
+	exclude     *regexp.Regexp // Lines matching are hidden
+	raw         bool           // Show JSON lines as received instead of formatted
+	fields      []string       // Fields shown of JSON lines, all when empty
+	where       []fieldCondition
+	whereText   string // Field conditions as typed
+}
+
+// keep reports whether a line passes the include and exclude filters, which match the raw
+// line, and the field conditions, which only JSON lines can meet
+func (v *logView) keep(line logLine) bool {
+	text := line.String()
+	if v.include != nil && !v.include.MatchString(text) {
+		return false
+	}
+	if v.exclude != nil && v.exclude.MatchString(text) {
+		return false
+	}
+	for _, c := range v.where {
+		if line.json == nil || !c.match(line.json) {
+			return false
+		}
+	}
+	return true
+}
+
+// spans returns the text of a line shown in the log pane, formatted if it is JSON
+func (v *logView) spans(line logLine) []span {
+	if line.json != nil && !v.raw {
+		// The color is reset for the lines that follow
+		return append(line.json.spans(v.fields), span{"", "[-]"})
+	}
+	return []span{{text: line.text}}
+}
+
+// queueLogsReceived queues a redraw of the log pane after lines arrived in buffer, unless one
+// is already queued. It never blocks, so that busy streams cannot flood the UI goroutine or be
+// held up by it.
+func (a *App) queueLogsReceived(buffer *logBuffer) {
+	if !buffer.pending.CompareAndSwap(false, true) {
+		return
+	}
+	go a.App.QueueUpdateDraw(func() {
+		buffer.pending.Store(false)
+		if a.logBuffer == buffer {
+			a.logsReceived()
+		}
+	})
+}
+
+// logsReceived redraws the log pane after lines arrived. While paused the lines are only
+// counted, so that the frozen text can be read and scrolled.
+func (a *App) logsReceived() {
+	if a.logView.paused {
+		a.updateLogsTitle()
+		return
+	}
+	a.renderLogs()
+}
+
+// renderLogs shows the filtered log buffer in LogsView with the search matches highlighted.
+// While paused, the lines kept when pausing are shown instead, so that the lines received since
+// then can neither show up nor push them out of the buffer.
+func (a *App) renderLogs() {
+	v := &a.logView
+	if a.logBuffer == nil {
+		return
+	}
+
+	lines, partial, _ := a.logBuffer.snapshot()
+	if v.paused {
+		lines = v.pausedLines
+	} else if partial != "" {
+		lines = append(lines, logLine{text: partial})
+	}
+	var b strings.Builder
+	matches, shown := 0, 0
+	for _, line := range lines {
+		if !v.keep(line) {
+			continue
+		}
+		if shown > 0 {
+			b.WriteString("\n")
+		}
+		shown++
+		if line.source != "" {
+			b.WriteString(line.color + tview.Escape(line.source) + "[-] ")
+		}
+		spans := v.spans(line)
+		var text strings.Builder
+		for _, s := range spans {
+			text.WriteString(s.text)
+		}
+		matches = writeSpans(&b, spans, matchRanges(text.String(), v.search), matches)
+	}
+	v.matches = matches
+	if v.current >= matches {
+		v.current = 0
+	}
+
+	a.LogsView.SetText(b.String())
+	a.LogsView.Highlight()
+	if matches > 0 {
+		a.LogsView.Highlight(fmt.Sprintf("match-%d", v.current)).ScrollToHighlight()
+	} else if !v.paused {
+		a.LogsView.ScrollToEnd()
+	}
+	a.updateLogsTitle()
+}
+
+// updateLogsTitle titles LogsView with the container, pause state, search and filters
+func (a *App) updateLogsTitle() {
+	v := &a.logView
+	title := " Logs "
+	if a.logSource != "" {
+		title = fmt.Sprintf(" Logs: %s ", tview.Escape(a.logSource))
+	}
+	if a.aggregator != nil {
+		title += fmt.Sprintf("(%d containers) ", a.aggregator.streamCount())
+	}
+	if v.paused {
+		received := 0
+		if a.logBuffer != nil {
+			_, _, received = a.logBuffer.snapshot()
+		}
+		title += fmt.Sprintf("[yellow]PAUSED +%d[-] ", received-v.pausedTotal)
+	}
+	if v.include != nil {
+		title += fmt.Sprintf("+/%s/ ", tview.Escape(v.include.String()))
+	}
+	if v.exclude != nil {
+		title += fmt.Sprintf("-/%s/ ", tview.Escape(v.exclude.String()))
+	}
+	if v.whereText != "" {
+		title += fmt.Sprintf("where %s ", tview.Escape(v.whereText))
+	}
+	if v.search != "" {
+		title += fmt.Sprintf("/%s [%d/%d] ", tview.Escape(v.search), min(v.current+1, v.matches), v.matches)
+	}
+	a.LogsView.SetTitle(title)
+}
+
+// togglePauseLogs freezes or resumes the log pane
+func (a *App) togglePauseLogs() {
+	v := &a.logView
+	v.paused = !v.paused
+	v.pausedLines = nil
+	if v.paused && a.logBuffer != nil {
+		v.pausedLines, _, v.pausedTotal = a.logBuffer.snapshot()
+	}
+	a.renderLogs()
+}
+
+// searchLogs sets the text highlighted in the log pane
+func (a *App) searchLogs(text string) {
+	a.logView.search = text
+	a.logView.current = 0
+	a.renderLogs()
+}
+
+// nextLogMatch highlights the next search match, or the previous one
+func (a *App) nextLogMatch(forward bool) {
+	v := &a.logView
+	if v.matches == 0 {
+		return
+	}
+	if forward {
+		v.current = (v.current + 1) % v.matches
+	} else {
+		v.current = (v.current + v.matches - 1) % v.matches
+	}
+	a.renderLogs()
+}
+
+// setLogFilter sets the include or exclude regular expression of the log pane. An empty
+// expression removes the filter.
+func (a *App) setLogFilter(include bool, expr string) error {
+	var re *regexp.Regexp
+	if expr != "" {
+		var err error
+		if re, err = regexp.Compile(expr); err != nil {
+			return err
+		}
+	}
+	if include {
+		a.logView.include = re
+	} else {
+		a.logView.exclude = re
+	}
+	a.renderLogs()
+	return nil
+}
+
+// showLogFilter opens an input bar to edit the include or exclude filter of the log pane
+func (a *App) showLogFilter(include bool) {
+	label, current := "exclude: ", a.logView.exclude
+	if include {
+		label, current = "include: ", a.logView.include
+	}
+	text := ""
+	if current != nil {
+		text = current.String()
+	}
+	a.showInputBar(label, text, func(text string) bool {
+		return a.setLogFilter(include, text) == nil
+	}, func() { _ = a.setLogFilter(include, "") })
+}
@@ -0,0 +1,351 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestView is the state of the manifest shown in InfoView
+type manifestView struct {
+	kind          *ResourceKind
+	obj           runtime.Object
+	json          bool   // Show JSON instead of YAML
+	managedFields bool   // Keep metadata.managedFields
+	foldStatus    bool   // Replace the status with a placeholder
+	search        string // Highlighted text, case-insensitive
+	matches       int
+	current       int
+}
+
+// Manifest syntax colors
+const (
+	manifestKeyColor     = "[#87afff]"
+	manifestStringColor  = "[#afd787]"
+	manifestLiteralColor = "[#d7afff]"
+	manifestCommentColor = "[gray]"
+)
+
+var (
+	yamlLineRegexp = regexp.MustCompile(`^(\s*(?:- )*)([^\s"'#][^:]*|"[^"]*"|'[^']*')(:)(\s+.*)?$`)
+	jsonLineRegexp = regexp.MustCompile(`^(\s*)("(?:[^"\\]|\\.)*")(:)(\s*.*)$`)
+	literalRegexp  = regexp.MustCompile(`^(-?[0-9.eE+-]+|true|false|null|~)$`)
+)
+
+// showManifest shows the full manifest of the object highlighted in the resource table
+func (a *App) showManifest() {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+
+	a.leaveDiff()
+	a.manifest = &manifestView{kind: kind, obj: obj}
+	if err := a.renderManifest(); err != nil {
+		a.manifest = nil
+		a.showError(fmt.Sprintf("Error showing manifest: %v", err))
+		return
+	}
+	a.CurrentFocus = 3
+	a.UpdateFocus()
+}
+
+// selectedObject returns the kind and live object of the row highlighted in the resource table
+func (a *App) selectedObject() (*ResourceKind, runtime.Object, bool) {
+	if a.viewingContainers || a.resourceWatch == nil {
+		return nil, nil, false
+	}
+	kind, ok := a.lookupKind(a.SelectedResourceType)
+	if !ok {
+		return nil, nil, false
+	}
+
+	// Informer caches key cluster-scoped objects by name alone
+	key := strings.TrimPrefix(a.ResourceList.SelectedKey(), "/")
+	item, exists, err := a.resourceWatch.informer.GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return nil, nil, false
+	}
+	obj, ok := item.(runtime.Object)
+	return kind, obj, ok
+}
+
+// renderManifest shows the current manifest in InfoView, highlighting search matches
+func (a *App) renderManifest() error {
+	m := a.manifest
+	text, err := manifestText(m.obj, m.json, m.managedFields, m.foldStatus)
+	if err != nil {
+		return err
+	}
+
+	highlighted, matches := highlightManifest(text, m.json, m.search)
+	m.matches = matches
+	if m.current >= matches {
+		m.current = 0
+	}
+
+	format := "YAML"
+	if m.json {
+		format = "JSON"
+	}
+	title := fmt.Sprintf(" %s %s ", m.kind.DisplayName, format)
+	if m.search != "" {
+		title += fmt.Sprintf("/%s [%d/%d] ", tview.Escape(m.search), min(m.current+1, matches), matches)
+	}
+
+	a.InfoView.SetTitle(title)
+	a.InfoView.SetText(highlighted)
+	a.InfoView.Highlight()
+	if matches > 0 {
+		a.InfoView.Highlight(fmt.Sprintf("match-%d", m.current)).ScrollToHighlight()
+	} else {
+		a.InfoView.ScrollToBeginning()
+	}
+	return nil
+}
+
+// closeManifest leaves the manifest view and returns to the resource table
+func (a *App) closeManifest() {
+	a.leaveManifest()
+	a.InfoView.Clear()
+	a.CurrentFocus = 2
+	a.UpdateFocus()
+}
+
+// leaveManifest forgets the manifest shown in InfoView, before other details replace it
+func (a *App) leaveManifest() {
+	if a.manifest == nil {
+		return
+	}
+	a.manifest = nil
+	a.InfoView.Highlight()
+	a.InfoView.SetTitle(" Info ")
+}
+
+// manifestText renders an object as YAML or JSON. managedFields are stripped unless requested,
+// and the status is replaced with a placeholder when folded.
+func manifestText(obj runtime.Object, asJSON, managedFields, foldStatus bool) (string, error) {
+	obj = obj.DeepCopyObject()
+
+	// Typed objects from informers have no apiVersion and kind set
+	if obj.GetObjectKind().GroupVersionKind().Empty() {
+		if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+			obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+		}
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", fmt.Errorf("error converting object: %v", err)
+	}
+	if !managedFields {
+		unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	}
+	if _, ok := content["status"]; ok && foldStatus {
+		content["status"] = "(folded)"
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error encoding JSON: %v", err)
+		}
+		return string(data), nil
+	}
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("error encoding YAML: %v", err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// span is a piece of a manifest line drawn in one color
+type span struct {
+	text  string
+	color string
+}
+
+// highlightManifest colors a manifest with tview tags and wraps the case-insensitive
+// occurrences of search in numbered regions. It returns the number of matches.
+func highlightManifest(text string, asJSON bool, search string) (string, int) {
+	var b strings.Builder
+	matches := 0
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		spans := colorManifestLine(line, asJSON)
+		matches = writeSpans(&b, spans, matchRanges(line, search), matches)
+	}
+	return b.String(), matches
+}
+
+// colorManifestLine splits a YAML or JSON line into colored spans
+func colorManifestLine(line string, asJSON bool) []span {
+	if !asJSON && strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return []span{{line, manifestCommentColor}}
+	}
+
+	lineRegexp := yamlLineRegexp
+	if asJSON {
+		lineRegexp = jsonLineRegexp
+	}
+	if m := lineRegexp.FindStringSubmatch(line); m != nil {
+		spans := []span{{m[1], "[white]"}, {m[2], manifestKeyColor}, {m[3], "[white]"}}
+		return append(spans, colorValue(m[4])...)
+	}
+
+	// List items and bare JSON values
+	trimmed := strings.TrimLeft(line, " -")
+	return append([]span{{line[:len(line)-len(trimmed)], "[white]"}}, colorValue(trimmed)...)
+}
+
+// colorValue colors a scalar value, keeping its leading whitespace and trailing comma
+func colorValue(value string) []span {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return []span{{value, "[white]"}}
+	}
+	leading := value[:strings.Index(value, trimmed)]
+	scalar := strings.TrimSuffix(trimmed, ",")
+	trailing := trimmed[len(scalar):]
+
+	color := manifestStringColor
+	switch {
+	case literalRegexp.MatchString(scalar):
+		color = manifestLiteralColor
+	case scalar == "{" || scalar == "}" || scalar == "[" || scalar == "]" || scalar == "{}" || scalar == "[]" || scalar == "|" || scalar == "|-":
+		color = "[white]"
+	}
+	return []span{{leading, "[white]"}, {scalar, color}, {trailing, "[white]"}}
+}
+
+// matchRanges returns the byte ranges of the case-insensitive occurrences of search in line
+func matchRanges(line, search string) [][2]int {
+	if search == "" {
+		return nil
+	}
+	var ranges [][2]int
+	lower, needle := strings.ToLower(line), strings.ToLower(search)
+	for start := 0; ; {
+		i := strings.Index(lower[start:], needle)
+		if i < 0 {
+			return ranges
+		}
+		ranges = append(ranges, [2]int{start + i, start + i + len(needle)})
+		start += i + len(needle)
+	}
+}
+
+// writeSpans writes colored spans, wrapping the given byte ranges in regions numbered from
+// first. It returns the number following the last region written.
+func writeSpans(b *strings.Builder, spans []span, ranges [][2]int, first int) int {
+	pos, r, open := 0, 0, false
+	for _, s := range spans {
+		b.WriteString(s.color)
+		text := s.text
+		for len(text) > 0 {
+			if !open && r < len(ranges) && pos == ranges[r][0] {
+				b.WriteString(fmt.Sprintf(`["match-%d"]`, first+r))
+				open = true
+			}
+			if open && pos == ranges[r][1] {
+				b.WriteString(`[""]`)
+				open = false
+				r++
+				continue
+			}
+
+			// Write up to the next region boundary
+			next := pos + len(text)
+			if r < len(ranges) {
+				if !open && ranges[r][0] < next {
+					next = ranges[r][0]
+				}
+				if open && ranges[r][1] < next {
+					next = ranges[r][1]
+				}
+			}
+			b.WriteString(tview.Escape(text[:next-pos]))
+			text = text[next-pos:]
+			pos = next
+		}
+	}
+	if open {
+		b.WriteString(`[""]`)
+		r++
+	}
+	return first + r
+}
+
+// handleInfoKey handles the manifest keys when InfoView is focused
+func (a *App) handleInfoKey(event *tcell.EventKey) *tcell.EventKey {
+	if a.diff != nil {
+		return a.handleDiffKey(event)
+	}
+	m := a.manifest
+	if m == nil {
+		if event.Key() == tcell.KeyEscape && a.CurrentFocus == 3 {
+			a.CurrentFocus = 2
+			a.UpdateFocus()
+			return nil
+		}
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape:
+		a.closeManifest()
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	switch event.Rune() {
+	case 'n':
+		if m.matches > 0 {
+			m.current = (m.current + 1) % m.matches
+		}
+	case 'N':
+		if m.matches > 0 {
+			m.current = (m.current + m.matches - 1) % m.matches
+		}
+	case 'j', 'J':
+		m.json = !m.json
+		m.current = 0
+	case 's', 'S':
+		m.foldStatus = !m.foldStatus
+		m.current = 0
+	case 'm', 'M':
+		m.managedFields = !m.managedFields
+		m.current = 0
+	default:
+		return event
+	}
+
+	if err := a.renderManifest(); err != nil {
+		a.showError(fmt.Sprintf("Error showing manifest: %v", err))
+	}
+	return nil
+}
+
+// searchManifest sets the text highlighted in the manifest
+func (a *App) searchManifest(text string) {
+	if a.manifest == nil {
+		return
+	}
+	a.manifest.search = text
+	a.manifest.current = 0
+	if err := a.renderManifest(); err != nil {
+		a.showError(fmt.Sprintf("Error showing manifest: %v", err))
+	}
+}
@@ -35,6 +35,10 @@ func (a *App) getCurrentFocus() tview.Primitive {
 		return a.ResourceTypeList
 	case 2:
 		return a.ResourceList
+	case 3:
+		return a.InfoView
+	case 4:
+		return a.LogsView
 	default:
 		return a.NsList
 	}
@@ -47,18 +51,20 @@ func (a *App) deleteCurrentResource() {
 		if a.SelectedNs == "" {
 			return
 		}
-		a.showConfirmationModal(
+		a.confirmDestructive(
 			"Delete Namespace",
 			fmt.Sprintf("Are you sure you want to delete namespace %s?\nThis action cannot be undone.", a.SelectedNs),
+			a.SelectedNs,
 			a.deleteSelectedNamespace,
 		)
 	case 1: // Pod
-		if a.SelectedPod == "" || a.CurrentNs == "" {
+		if a.SelectedPod == "" || a.podNamespace() == "" {
 			return
 		}
-		a.showConfirmationModal(
+		a.confirmDestructive(
 			"Delete Pod",
-			fmt.Sprintf("Are you sure you want to delete pod %s in namespace %s?\nThis action cannot be undone.", a.SelectedPod, a.CurrentNs),
+			fmt.Sprintf("Are you sure you want to delete pod %s in namespace %s?\nThis action cannot be undone.", a.SelectedPod, a.podNamespace()),
+			a.SelectedPod,
 			a.deleteSelectedPod,
 		)
 	}
@@ -83,11 +89,11 @@ func (a *App) deleteSelectedNamespace() {
 
 // deleteSelectedPod deletes the currently selected pod
 func (a *App) deleteSelectedPod() {
-	if a.KubeClient == nil || a.CurrentNs == "" || a.SelectedPod == "" {
+	if a.KubeClient == nil || a.podNamespace() == "" || a.SelectedPod == "" {
 		return
 	}
 
-	err := a.KubeClient.CoreV1().Pods(a.CurrentNs).Delete(a.getContext(), a.SelectedPod, metav1.DeleteOptions{})
+	err := a.KubeClient.CoreV1().Pods(a.podNamespace()).Delete(a.getContext(), a.SelectedPod, metav1.DeleteOptions{})
 	if err != nil {
 		a.showError(fmt.Sprintf("Error deleting pod: %v", err))
 		return
@@ -95,6 +101,7 @@ func (a *App) deleteSelectedPod() {
 
 	// Clear selection and reload pods
 	a.SelectedPod = ""
+	a.SelectedPodNs = ""
 	a.LoadPods()
 }
 
@@ -111,3 +118,28 @@ func (a *App) showError(message string) {
 	a.pages.AddPage("error", modal, true, true)
 	a.App.SetFocus(modal)
 }
+
+// queueUpdateDraw runs f on the UI goroutine and redraws. It is called from background work.
+func (a *App) queueUpdateDraw(f func()) {
+	if a.updates != nil {
+		a.updates <- f
+		return
+	}
+	a.App.QueueUpdateDraw(f)
+}
+
+// showStatus shows the outcome of an action in the status bar
+func (a *App) showStatus(message string) {
+	a.StatusBar.SetText(" " + tview.Escape(message))
+}
+
+// modalFrame centers a primitive of the given size on top of the main UI
+func modalFrame(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
@@ -3,10 +3,15 @@ package app
 
 // navigate handles keyboard navigation between UI elements
 func (a *App) navigate(forward bool) {
+	// LogsView can only be focused while it is shown, with the containers of a pod or aggregated logs
+	panes := 4
+	if a.viewingContainers || a.aggregator != nil {
+		panes = 5
+	}
 	if forward {
-		a.CurrentFocus = (a.CurrentFocus + 1) % 3
+		a.CurrentFocus = (a.CurrentFocus + 1) % panes
 	} else {
-		a.CurrentFocus = (a.CurrentFocus + 2) % 3 // +2 is equivalent to -1 mod 3
+		a.CurrentFocus = (a.CurrentFocus + panes - 1) % panes
 	}
 	a.UpdateFocus()
 }
@@ -20,5 +25,9 @@ func (a *App) UpdateFocus() {
 		a.App.SetFocus(a.ResourceTypeList)
 	case 2:
 		a.App.SetFocus(a.ResourceList)
+	case 3:
+		a.App.SetFocus(a.InfoView)
+	case 4:
+		a.App.SetFocus(a.LogsView)
 	}
 }
@@ -0,0 +1,531 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// Port forward states
+const (
+	forwardStarting = "Starting"
+	forwardActive   = "Active"
+	forwardFailed   = "Failed"
+	forwardStopped  = "Stopped"
+)
+
+// portForward forwards a local port to a port of a pod until it is stopped
+type portForward struct {
+	context   string // Kube context the forward was opened in
+	target    string // Pod or service the forward was opened on, as kind/name
+	namespace string
+	pod       string
+	local     int
+	remote    int
+	started   time.Time
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	bytesIn   atomic.Int64 // Received from the pod
+	bytesOut  atomic.Int64 // Sent to the pod
+
+	mu     sync.Mutex
+	status string
+	err    error
+}
+
+// state returns the status of the forward and the error it failed with, if any
+func (f *portForward) state() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status, f.err
+}
+
+// setState records the status of the forward
+func (f *portForward) setState(status string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status, f.err = status, err
+}
+
+// stop closes the local port and the connection to the pod
+func (f *portForward) stop() {
+	f.stopOnce.Do(func() { close(f.stopCh) })
+}
+
+// parsePortPair parses local:remote, :remote (any free local port) or a single port used on
+// both sides
+func parsePortPair(text string) (int, int, error) {
+	localText, remoteText, found := strings.Cut(strings.TrimSpace(text), ":")
+	if !found {
+		remoteText = localText
+	}
+	if localText == "" {
+		localText = "0"
+	}
+	local, err := strconv.ParseUint(localText, 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid local port %q", localText)
+	}
+	remote, err := strconv.ParseUint(remoteText, 10, 16)
+	if err != nil || remote == 0 {
+		return 0, 0, fmt.Errorf("invalid remote port %q", remoteText)
+	}
+	return int(local), int(remote), nil
+}
+
+// showPortForwardPrompt asks for the ports to forward to the pod or service highlighted in
+// the resource table
+func (a *App) showPortForwardPrompt() {
+	_, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+	var name string
+	var port int32
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		name = "pod/" + o.Name
+		for _, c := range o.Spec.Containers {
+			if len(c.Ports) > 0 {
+				port = c.Ports[0].ContainerPort
+				break
+			}
+		}
+	case *corev1.Service:
+		name = "service/" + o.Name
+		if len(o.Spec.Ports) > 0 {
+			port = o.Spec.Ports[0].Port
+		}
+	default:
+		a.showError("Ports can only be forwarded to pods and services")
+		return
+	}
+
+	text := ""
+	if port > 0 {
+		text = fmt.Sprintf("%d:%d", port, port)
+	}
+	a.showPrompt(fmt.Sprintf("forward to %s (local:remote): ", name), text, func(text string) {
+		err := a.startPortForward(obj, text, func(f *portForward, err error) {
+			if err != nil {
+				a.showError(fmt.Sprintf("Error forwarding to %s: %v", name, err))
+				return
+			}
+			a.showStatus(fmt.Sprintf("Forwarding localhost:%d to %s port %d (Ctrl+P lists forwards)", f.local, name, f.remote))
+		})
+		if err != nil {
+			a.showError(fmt.Sprintf("Error forwarding to %s: %v", name, err))
+			return
+		}
+		a.showStatus(fmt.Sprintf("Starting port forward to %s...", name))
+	})
+}
+
+// startPortForward forwards ports given as local:remote to a pod, or to a pod backing a
+// service. The ports are checked at once, while the pod is resolved and the connection opened
+// in the background, so that a slow API server does not freeze the UI. started is then called
+// on the UI goroutine with the forward, once its local port is open, or with the error it
+// failed with. The forward keeps running until it is stopped or the application exits.
+func (a *App) startPortForward(obj runtime.Object, ports string, started func(f *portForward, err error)) error {
+	local, remote, err := parsePortPair(ports)
+	if err != nil {
+		return err
+	}
+
+	var target string
+	var pod *corev1.Pod
+	var svc *corev1.Service
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		target, pod = "pod/"+o.Name, o
+		if pod.Status.Phase != corev1.PodRunning {
+			return fmt.Errorf("pod %s is not running", pod.Name)
+		}
+	case *corev1.Service:
+		target, svc = "service/"+o.Name, o
+		if a.KubeClient == nil {
+			return fmt.Errorf("kubernetes client not initialized")
+		}
+	default:
+		return fmt.Errorf("ports can only be forwarded to pods and services")
+	}
+	dial, err := a.portForwardDialer()
+	if err != nil {
+		return err
+	}
+
+	f := &portForward{
+		context: a.CurrentContext,
+		target:  target,
+		local:   local,
+		remote:  remote,
+		stopCh:  make(chan struct{}),
+		status:  forwardStarting,
+	}
+	client := a.KubeClient
+	go func() {
+		var err error
+		if svc != nil {
+			pod, f.remote, err = resolveServicePort(client, svc, remote)
+		}
+		if err == nil {
+			err = a.runPortForward(f, pod, dial)
+		}
+		a.queueUpdateDraw(func() {
+			if err != nil {
+				started(nil, err)
+				return
+			}
+			a.portForwards = append(a.portForwards, f)
+			started(f, nil)
+		})
+	}()
+	return nil
+}
+
+// runPortForward opens the local port of f and forwards it to pod in the background. It
+// returns once the port is open, so that its number is known and a port already in use is
+// reported right away.
+func (a *App) runPortForward(f *portForward, pod *corev1.Pod, dial func(namespace, pod string) (httpstream.Dialer, error)) error {
+	f.namespace, f.pod, f.started = pod.Namespace, pod.Name, time.Now()
+	dialer, err := dial(pod.Namespace, pod.Name)
+	if err != nil {
+		return err
+	}
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(&countingDialer{Dialer: dialer, forward: f}, []string{"localhost"},
+		[]string{fmt.Sprintf("%d:%d", f.local, f.remote)}, f.stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return fmt.Errorf("error creating port forward: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := forwarder.ForwardPorts()
+		status, _ := f.state()
+		if err == nil {
+			f.setState(forwardStopped, nil)
+		} else {
+			f.setState(forwardFailed, err)
+		}
+		done <- err
+
+		// A forward that was working is reported when it breaks, for instance when its pod is
+		// deleted
+		if err != nil && status == forwardActive {
+			a.queueUpdateDraw(func() {
+				a.showStatus(fmt.Sprintf("Port forward localhost:%d to %s failed: %v", f.local, f.target, err))
+			})
+		}
+	}()
+
+	select {
+	case <-readyCh:
+		if forwarded, err := forwarder.GetPorts(); err == nil && len(forwarded) > 0 {
+			f.local = int(forwarded[0].Local)
+		}
+		f.setState(forwardActive, nil)
+		return nil
+	case err := <-done:
+		return err
+	}
+}
+
+// resolveServicePort picks a ready pod backing a service and the container port a service
+// port is forwarded to, as kubectl port-forward does
+func resolveServicePort(client kubernetes.Interface, svc *corev1.Service, port int) (*corev1.Pod, int, error) {
+	var servicePort *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if int(svc.Spec.Ports[i].Port) == port {
+			servicePort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return nil, 0, fmt.Errorf("service %s has no port %d", svc.Name, port)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, 0, fmt.Errorf("service %s has no selector", svc.Name)
+	}
+	list, err := client.CoreV1().Pods(svc.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing pods of service %s: %v", svc.Name, err)
+	}
+	pods := list.Items
+	sort.Slice(pods, func(i, j int) bool {
+		if ri, rj := isPodReady(&pods[i]), isPodReady(&pods[j]); ri != rj {
+			return ri
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	var pod *corev1.Pod
+	for i := range pods {
+		if pods[i].Status.Phase == corev1.PodRunning {
+			pod = &pods[i]
+			break
+		}
+	}
+	if pod == nil {
+		return nil, 0, fmt.Errorf("service %s has no running pods", svc.Name)
+	}
+
+	target := servicePort.TargetPort
+	switch {
+	case target.Type == intstr.String && target.StrVal != "":
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == target.StrVal {
+					return pod, int(p.ContainerPort), nil
+				}
+			}
+		}
+		return nil, 0, fmt.Errorf("pod %s has no port named %s", pod.Name, target.StrVal)
+	case target.IntValue() > 0:
+		return pod, target.IntValue(), nil
+	}
+	return pod, port, nil
+}
+
+// isPodReady reports whether a pod is running and ready
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// portForwardDialer returns the function that creates the dialer of the port-forward
+// subresource of a pod in the current context. It may be called off the UI goroutine.
+func (a *App) portForwardDialer() (func(namespace, pod string) (httpstream.Dialer, error), error) {
+	if a.forwardDialer != nil {
+		return a.forwardDialer, nil
+	}
+	if a.KubeClient == nil || a.RestConfig == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	client, config := a.KubeClient, a.RestConfig
+	return func(namespace, pod string) (httpstream.Dialer, error) {
+		transport, upgrader, err := spdy.RoundTripperFor(config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating SPDY transport: %v", err)
+		}
+		req := client.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod).
+			SubResource("portforward")
+		return spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL()), nil
+	}, nil
+}
+
+// stopPortForward stops a forward and removes it from the list
+func (a *App) stopPortForward(f *portForward) {
+	f.stop()
+	for i, other := range a.portForwards {
+		if other == f {
+			a.portForwards = append(a.portForwards[:i], a.portForwards[i+1:]...)
+			break
+		}
+	}
+}
+
+// stopPortForwards stops every forward and the refresh of the port forward panel
+func (a *App) stopPortForwards() {
+	for _, f := range a.portForwards {
+		f.stop()
+	}
+	a.portForwards = nil
+	a.stopPortForwardsRefresh()
+}
+
+// stopPortForwardsRefresh stops refreshing the port forward panel, if it is open
+func (a *App) stopPortForwardsRefresh() {
+	if a.portForwardsRefresh != nil {
+		close(a.portForwardsRefresh)
+		a.portForwardsRefresh = nil
+	}
+}
+
+// showPortForwards shows the forwards with their status and traffic. The panel is refreshed
+// every second while open.
+func (a *App) showPortForwards() {
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle(" Port forwards ([::b]S[::-] stop, [::b]Esc/Q[::-] close) ")
+	a.renderPortForwards(table)
+
+	a.stopPortForwardsRefresh()
+	stop := make(chan struct{})
+	a.portForwardsRefresh = stop
+	closePanel := func() {
+		a.stopPortForwardsRefresh()
+		a.pages.RemovePage("portforwards")
+		a.App.SetFocus(a.getCurrentFocus())
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		// Refreshes are queued without waiting for them, which never happens once the
+		// application stopped, and only while none is pending
+		var pending atomic.Bool
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if pending.CompareAndSwap(false, true) {
+					go a.queueUpdateDraw(func() {
+						pending.Store(false)
+						a.renderPortForwards(table)
+					})
+				}
+			}
+		}
+	}()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Key() == tcell.KeyRune && (event.Rune() == 'q' || event.Rune() == 'Q'):
+			closePanel()
+			return nil
+		case event.Key() == tcell.KeyDelete, event.Key() == tcell.KeyRune && (event.Rune() == 's' || event.Rune() == 'S'):
+			if row, _ := table.GetSelection(); row > 0 && row <= len(a.portForwards) {
+				a.stopPortForward(a.portForwards[row-1])
+				a.renderPortForwards(table)
+			}
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage("portforwards", modalFrame(table, 110, len(a.portForwards)+4), true, true)
+	a.App.SetFocus(table)
+}
+
+// renderPortForwards fills the port forward panel
+func (a *App) renderPortForwards(table *tview.Table) {
+	row, _ := table.GetSelection()
+	table.Clear()
+	for i, header := range []string{"TARGET", "POD", "LOCAL", "REMOTE", "STATUS", "IN", "OUT", "AGE", "CONTEXT"} {
+		table.SetCell(0, i, tview.NewTableCell(header).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+	}
+	if len(a.portForwards) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No active port forwards").SetSelectable(false))
+		return
+	}
+
+	for i, f := range a.portForwards {
+		status, err := f.state()
+		color := tcell.ColorDefault
+		switch status {
+		case forwardActive:
+			color = tcell.ColorGreen
+		case forwardFailed:
+			status, color = fmt.Sprintf("%s: %v", status, err), tcell.ColorRed
+		}
+		cells := []string{
+			f.namespace + "/" + f.target,
+			f.pod,
+			fmt.Sprintf("localhost:%d", f.local),
+			fmt.Sprint(f.remote),
+			status,
+			formatBytes(f.bytesIn.Load()),
+			formatBytes(f.bytesOut.Load()),
+			getAge(f.started),
+			f.context,
+		}
+		for j, text := range cells {
+			cell := tview.NewTableCell(tview.Escape(text))
+			if j == 4 {
+				cell.SetTextColor(color)
+			}
+			table.SetCell(i+1, j, cell)
+		}
+	}
+	table.Select(min(max(row, 1), len(a.portForwards)), 0)
+}
+
+// countingDialer counts the bytes a port forward carries
+type countingDialer struct {
+	httpstream.Dialer
+	forward *portForward
+}
+
+// Dial opens a connection whose data streams are counted
+func (d *countingDialer) Dial(protocols ...string) (httpstream.Connection, string, error) {
+	conn, protocol, err := d.Dialer.Dial(protocols...)
+	if err != nil {
+		return nil, "", err
+	}
+	return &countingConnection{Connection: conn, forward: d.forward}, protocol, nil
+}
+
+// countingConnection wraps the data streams of a port forward connection to count their bytes
+type countingConnection struct {
+	httpstream.Connection
+	forward *portForward
+}
+
+// CreateStream creates a stream, counting the bytes of data streams
+func (c *countingConnection) CreateStream(headers http.Header) (httpstream.Stream, error) {
+	stream, err := c.Connection.CreateStream(headers)
+	if err != nil || headers.Get(corev1.StreamType) != corev1.StreamTypeData {
+		return stream, err
+	}
+	return &countingStream{Stream: stream, forward: c.forward}, nil
+}
+
+// RemoveStreams removes streams from the connection, unwrapping counted streams
+func (c *countingConnection) RemoveStreams(streams ...httpstream.Stream) {
+	for i, s := range streams {
+		if counted, ok := s.(*countingStream); ok {
+			streams[i] = counted.Stream
+		}
+	}
+	c.Connection.RemoveStreams(streams...)
+}
+
+// countingStream is a data stream of a port forward whose bytes are counted
+type countingStream struct {
+	httpstream.Stream
+	forward *portForward
+}
+
+// Read reads bytes sent by the pod
+func (s *countingStream) Read(p []byte) (int, error) {
+	n, err := s.Stream.Read(p)
+	s.forward.bytesIn.Add(int64(n))
+	return n, err
+}
+
+// Write writes bytes to the pod
+func (s *countingStream) Write(p []byte) (int, error) {
+	n, err := s.Stream.Write(p)
+	s.forward.bytesOut.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// protectedColor is the color of the frame and title of a protected context
+const protectedColor = tcell.ColorRed
+
+// matchContextPattern reports whether a context name matches a pattern where * matches any
+// text, including the slashes of EKS and GKE context names, and ? any single character
+func matchContextPattern(pattern, name string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	matched, _ := regexp.MatchString("^"+expr+"$", name)
+	return matched
+}
+
+// isProtected reports whether the current context is protected, so that destructive actions
+// require typing the name of what they destroy. The setting of the context in the config file
+// overrides the protected context patterns.
+func (a *App) isProtected() bool {
+	if context, ok := a.config.Contexts[a.CurrentContext]; ok && context.Protected != nil {
+		return *context.Protected
+	}
+	for _, pattern := range a.config.ProtectedContexts {
+		if matchContextPattern(pattern, a.CurrentContext) {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmDestructive asks to confirm a destructive action. In a protected context the name of
+// what it destroys must be typed instead of answering Yes.
+func (a *App) confirmDestructive(title, message, name string, callback func()) {
+	if a.isProtected() {
+		a.showTypedConfirmation(title, message, name, callback)
+		return
+	}
+	a.showConfirmationModal(title, message, callback)
+}
+
+// guardDestructive runs a destructive action that needs no confirmation, unless the context is
+// protected, where the name of what it destroys must be typed first
+func (a *App) guardDestructive(title, message, name string, callback func()) {
+	if a.isProtected() {
+		a.showTypedConfirmation(title, message, name, callback)
+		return
+	}
+	callback()
+}
+
+// showTypedConfirmation asks to type a name to confirm a destructive action
+func (a *App) showTypedConfirmation(title, message, name string, callback func()) {
+	text := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	text.SetText(fmt.Sprintf("%s\n\n[red::b]%s is a protected context.[-::-] Type [::b]%s[::-] to confirm, or press Esc to cancel:",
+		tview.Escape(message), tview.Escape(a.CurrentContext), tview.Escape(name)))
+	input := tview.NewInputField().SetLabel("> ").SetFieldBackgroundColor(tcell.ColorDefault)
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.pages.RemovePage("confirmation")
+		a.App.SetFocus(a.getCurrentFocus())
+		if key != tcell.KeyEnter {
+			return
+		}
+		if input.GetText() != name {
+			a.showStatus(fmt.Sprintf("%s cancelled: the typed name does not match %s", title, name))
+			return
+		}
+		callback()
+	})
+
+	frame := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	frame.SetBorder(true).SetBorderColor(protectedColor).
+		SetTitle(fmt.Sprintf(" %s in %s ", tview.Escape(title), tview.Escape(a.CurrentContext)))
+
+	a.pages.AddPage("confirmation", modalFrame(frame, 70, 10), true, true)
+	a.App.SetFocus(input)
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// mutation is a kind of action that modifies the cluster, described as read-only mode
+// reports it
+type mutation string
+
+// Actions refused in read-only mode
+const (
+	mutationDelete      mutation = "deleting resources"
+	mutationEdit        mutation = "editing resources"
+	mutationExec        mutation = "exec into containers"
+	mutationPortForward mutation = "port forwarding"
+	mutationScale       mutation = "scaling"
+	mutationRollout     mutation = "changing rollouts"
+)
+
+// hotkey is an entry of the hotkey help in the main frame title
+type hotkey struct {
+	keys     string
+	label    string
+	mutation mutation // What the key modifies, if anything; hidden in read-only mode
+}
+
+// hotkeys is the hotkey help in the main frame title
+var hotkeys = []hotkey{
+	{"TAB/Shift+TAB", "Navigate", ""},
+	{"ENTER", "Select", ""},
+	{"Ctrl+D", "Delete", mutationDelete},
+	{"Q", "Quit", ""},
+	{"↑/↓/←/→", "Scroll", ""},
+	{"Ctrl+R", "Resource Types", ""},
+	{"C", "Contexts", ""},
+	{"X", "Shell", mutationExec},
+	{"1-9", "Sort", ""},
+	{"W", "Wide", ""},
+	{"/", "Filter", ""},
+	{"Y", "YAML", ""},
+	{"E", "Edit", mutationEdit},
+	{"D", "Describe", ""},
+	{"L", "Logs", ""},
+	{"F", "Port-forward", mutationPortForward},
+	{"Ctrl+P", "Forwards", ""},
+	{"S", "Scale", mutationScale},
+	{"Z", "Scale to 0/restore", mutationScale},
+	{"O", "Rollout", mutationRollout},
+	{"V", "Diff last-applied", ""},
+}
+
+// resourceKeyMutations maps the resource table keys that modify the cluster to what they
+// modify, and containerKeyMutations those of the containers of a pod
+var (
+	resourceKeyMutations = map[rune]mutation{
+		'e': mutationEdit,
+		'f': mutationPortForward,
+		'o': mutationRollout,
+		's': mutationScale,
+		'z': mutationScale,
+	}
+	containerKeyMutations = map[rune]mutation{
+		'x': mutationExec,
+	}
+)
+
+// isReadOnly reports whether actions that modify the cluster are refused in the current
+// context. The -readonly flag always applies; otherwise the setting of the context in the
+// config file overrides the global one.
+func (a *App) isReadOnly() bool {
+	if a.ReadOnly {
+		return true
+	}
+	if context, ok := a.config.Contexts[a.CurrentContext]; ok && context.ReadOnly != nil {
+		return *context.ReadOnly
+	}
+	return a.config.ReadOnly
+}
+
+// allow reports whether an action may modify the cluster, explaining in the status bar why
+// it may not
+func (a *App) allow(m mutation) bool {
+	if !a.isReadOnly() {
+		return true
+	}
+	a.showStatus(fmt.Sprintf("Read-only mode: %s is disabled", m))
+	return false
+}
+
+// mutationOf returns what a key modifies in the cluster where it is pressed, if anything
+func (a *App) mutationOf(event *tcell.EventKey) (mutation, bool) {
+	if event.Key() == tcell.KeyCtrlD {
+		return mutationDelete, true
+	}
+	if event.Key() != tcell.KeyRune || a.App.GetFocus() != a.ResourceList {
+		return "", false
+	}
+	mutations := resourceKeyMutations
+	if a.viewingContainers {
+		mutations = containerKeyMutations
+	}
+	m, ok := mutations[unicode.ToLower(event.Rune())]
+	return m, ok
+}
+
+// refuseMutation reports whether a key is refused because it modifies the cluster in
+// read-only mode. Every key goes through it before reaching its handler.
+func (a *App) refuseMutation(event *tcell.EventKey) bool {
+	m, ok := a.mutationOf(event)
+	return ok && !a.allow(m)
+}
+
+// readOnlyTransport refuses every request that could modify the cluster while the app is in
+// read-only mode, including exec and port forwarding, should an action not be refused earlier
+type readOnlyTransport struct {
+	app  *App
+	next http.RoundTripper
+}
+
+// RoundTrip only lets through requests that read from the cluster in read-only mode
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if t.app.isReadOnly() {
+			return nil, fmt.Errorf("read-only mode: %s %s refused", req.Method, req.URL.Path)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,336 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceKind describes how a resource type is listed and displayed
+type ResourceKind struct {
+	Type        ResourceType
+	DisplayName string
+	GVR         schema.GroupVersionResource
+	Namespaced  bool
+	Dynamic     bool                                   // Listed as unstructured objects through the dynamic client
+	Columns     []string                               // Column headers shown after NAME
+	Row         func(obj runtime.Object) []string      // Column values of an object, matching Columns, unless the kind fills its own table
+	Detail      func(a *App, obj runtime.Object) error // Renders the selected object; nil shows its metadata
+}
+
+// resourceKinds is the registry of every supported resource type, in display order
+var resourceKinds = []*ResourceKind{
+	{
+		Type: ResourceTypeDeployment, DisplayName: "Deployments", Namespaced: true,
+		GVR:     appsv1.SchemeGroupVersion.WithResource("deployments"),
+		Columns: []string{"READY", "UP-TO-DATE", "AVAILABLE", "AGE"}, Row: deploymentRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.showDeploymentInfo(obj.(*appsv1.Deployment)) },
+	},
+	{
+		Type: ResourceTypeReplicaSet, DisplayName: "ReplicaSets", Namespaced: true,
+		GVR:     appsv1.SchemeGroupVersion.WithResource("replicasets"),
+		Columns: []string{"DESIRED", "CURRENT", "READY", "AGE"}, Row: replicaSetRow,
+	},
+	{
+		Type: ResourceTypeStatefulSet, DisplayName: "StatefulSets", Namespaced: true,
+		GVR:     appsv1.SchemeGroupVersion.WithResource("statefulsets"),
+		Columns: []string{"READY", "AGE"}, Row: statefulSetRow,
+	},
+	{
+		Type: ResourceTypeDaemonSet, DisplayName: "DaemonSets", Namespaced: true,
+		GVR:     appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+		Columns: []string{"DESIRED", "CURRENT", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}, Row: daemonSetRow,
+	},
+	{
+		Type: ResourceTypeJob, DisplayName: "Jobs", Namespaced: true,
+		GVR:     batchv1.SchemeGroupVersion.WithResource("jobs"),
+		Columns: []string{"COMPLETIONS", "AGE"}, Row: jobRow,
+	},
+	{
+		Type: ResourceTypeCronJob, DisplayName: "CronJobs", Namespaced: true,
+		GVR:     batchv1.SchemeGroupVersion.WithResource("cronjobs"),
+		Columns: []string{"SCHEDULE", "SUSPEND", "ACTIVE", "LAST SCHEDULE", "AGE"}, Row: cronJobRow,
+	},
+	{
+		Type: ResourceTypeService, DisplayName: "Services", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("services"),
+		Columns: []string{"TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE"}, Row: serviceRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.showServiceInfo(obj.(*corev1.Service)) },
+	},
+	{
+		Type: ResourceTypeConfigMap, DisplayName: "ConfigMaps", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("configmaps"),
+		Columns: []string{"DATA", "AGE"}, Row: configMapRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.showConfigMapInfo(obj.(*corev1.ConfigMap)) },
+	},
+	{
+		Type: ResourceTypeSecret, DisplayName: "Secrets", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("secrets"),
+		Columns: []string{"TYPE", "DATA", "AGE"}, Row: secretRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.showSecretInfo(obj.(*corev1.Secret)) },
+	},
+	{
+		Type: ResourceTypeIngress, DisplayName: "Ingresses", Namespaced: true,
+		GVR:     netv1.SchemeGroupVersion.WithResource("ingresses"),
+		Columns: []string{"CLASS", "HOSTS", "ADDRESS", "AGE"}, Row: ingressRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.showIngressInfo(obj.(*netv1.Ingress)) },
+	},
+	{
+		Type: ResourceTypeNetworkPolicy, DisplayName: "NetworkPolicies", Namespaced: true,
+		GVR:     netv1.SchemeGroupVersion.WithResource("networkpolicies"),
+		Columns: []string{"POD-SELECTOR", "POLICY-TYPES", "AGE"}, Row: networkPolicyRow,
+	},
+	{
+		Type: ResourceTypePVC, DisplayName: "PersistentVolumeClaims", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"),
+		Columns: []string{"STATUS", "VOLUME", "CAPACITY", "AGE"}, Row: pvcRow,
+	},
+	{
+		Type: ResourceTypePV, DisplayName: "PersistentVolumes",
+		GVR:     corev1.SchemeGroupVersion.WithResource("persistentvolumes"),
+		Columns: []string{"CAPACITY", "STATUS", "CLAIM", "AGE"}, Row: pvRow,
+	},
+	{
+		Type: ResourceTypeServiceAccount, DisplayName: "ServiceAccounts", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("serviceaccounts"),
+		Columns: []string{"SECRETS", "AGE"}, Row: serviceAccountRow,
+	},
+	{
+		Type: ResourceTypeRole, DisplayName: "Roles", Namespaced: true,
+		GVR:     rbacv1.SchemeGroupVersion.WithResource("roles"),
+		Columns: []string{"RULES", "AGE"}, Row: roleRow,
+	},
+	{
+		Type: ResourceTypeRoleBinding, DisplayName: "RoleBindings", Namespaced: true,
+		GVR:     rbacv1.SchemeGroupVersion.WithResource("rolebindings"),
+		Columns: []string{"ROLE", "SUBJECTS", "AGE"}, Row: roleBindingRow,
+	},
+	{
+		Type: ResourceTypeClusterRole, DisplayName: "ClusterRoles",
+		GVR:     rbacv1.SchemeGroupVersion.WithResource("clusterroles"),
+		Columns: []string{"RULES", "AGE"}, Row: clusterRoleRow,
+	},
+	{
+		Type: ResourceTypeClusterRoleBinding, DisplayName: "ClusterRoleBindings",
+		GVR:     rbacv1.SchemeGroupVersion.WithResource("clusterrolebindings"),
+		Columns: []string{"ROLE", "SUBJECTS", "AGE"}, Row: clusterRoleBindingRow,
+	},
+	{
+		Type: ResourceTypeEndpoint, DisplayName: "Endpoints", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("endpoints"),
+		Columns: []string{"ENDPOINTS", "AGE"}, Row: endpointsRow,
+	},
+	{
+		Type: ResourceTypeHPA, DisplayName: "HorizontalPodAutoscalers", Namespaced: true,
+		GVR:     autoscalingv1.SchemeGroupVersion.WithResource("horizontalpodautoscalers"),
+		Columns: []string{"REFERENCE", "MINPODS", "MAXPODS", "REPLICAS", "AGE"}, Row: hpaRow,
+	},
+	{
+		Type: ResourceTypeLimitRange, DisplayName: "LimitRanges", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("limitranges"),
+		Columns: []string{"AGE"}, Row: ageRow,
+	},
+	{
+		Type: ResourceTypeResourceQuota, DisplayName: "ResourceQuotas", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("resourcequotas"),
+		Columns: []string{"AGE"}, Row: ageRow,
+	},
+	{
+		// fillEventTable builds the rows, collapsing repeated events under their object
+		Type: ResourceTypeEvent, DisplayName: "Events", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("events"),
+		Columns: []string{"LAST SEEN", "TYPE", "REASON", "COUNT", "MESSAGE"},
+	},
+	{
+		Type: ResourceTypeNode, DisplayName: "Nodes",
+		GVR:     corev1.SchemeGroupVersion.WithResource("nodes"),
+		Columns: []string{"STATUS", "ROLES", "AGE", "VERSION"}, Row: nodeRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.showNodeInfo(obj.(*corev1.Node)) },
+	},
+	{
+		Type: ResourceTypePod, DisplayName: "Pods", Namespaced: true,
+		GVR:     corev1.SchemeGroupVersion.WithResource("pods"),
+		Columns: []string{"READY", "STATUS", "RESTARTS", "AGE", "IP", "NODE"}, Row: podRow,
+		Detail: func(a *App, obj runtime.Object) error { return a.selectPod(obj.(*corev1.Pod)) },
+	},
+	{
+		Type: ResourceTypeNamespace, DisplayName: "Namespaces",
+		GVR:     corev1.SchemeGroupVersion.WithResource("namespaces"),
+		Columns: []string{"STATUS", "AGE"}, Row: namespaceRow,
+	},
+}
+
+// lookupResourceKind returns the registry entry of a resource type
+func lookupResourceKind(resourceType ResourceType) (*ResourceKind, bool) {
+	for _, kind := range resourceKinds {
+		if kind.Type == resourceType {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// lookupKind returns the registry entry of a resource type, including kinds discovered in the cluster
+func (a *App) lookupKind(resourceType ResourceType) (*ResourceKind, bool) {
+	if kind, ok := lookupResourceKind(resourceType); ok {
+		return kind, true
+	}
+	for _, kind := range a.discoveredKinds {
+		if kind.Type == resourceType {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// LoadResources shows the resources of the given type in the resource list
+func (a *App) LoadResources(resourceType ResourceType) error {
+	kind, ok := a.lookupKind(resourceType)
+	if !ok {
+		return fmt.Errorf("unsupported resource type: %s", resourceType)
+	}
+	if a.KubeClient == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+
+	namespace := metav1.NamespaceAll
+	if kind.Namespaced && !a.allNamespaces {
+		if a.CurrentNs == "" {
+			return fmt.Errorf("no namespace selected")
+		}
+		namespace = a.CurrentNs
+	}
+
+	// A filter only applies to the list it was typed in
+	if a.viewingContainers || a.SelectedResourceType != kind.Type {
+		a.ResourceList.SetFilter(nil)
+	}
+	a.viewingContainers = false
+	a.SelectedResourceType = kind.Type
+	a.leaveManifest()
+	a.leaveDiff()
+	if a.listsNamespaces(kind) {
+		a.setResourceListTitle(kind.DisplayName + " (all namespaces)")
+	} else {
+		a.setResourceListTitle(kind.DisplayName)
+	}
+
+	// Hide logs window when displaying resources, and stop aggregated logs with it
+	if a.aggregator != nil {
+		a.stopLogStream()
+	}
+	a.showLogsWindow(false)
+
+	// Repeated events are collapsed into one row, which the server cannot print
+	if kind.Type == ResourceTypeEvent {
+		a.stopTableWatch()
+		return a.watchResources(kind.GVR, namespace, kind.Dynamic, func(objects []runtime.Object) func() {
+			return func() { a.fillEventTable(kind, objects) }
+		})
+	}
+
+	// Columns come from the API server, as kubectl get prints them, once it has listed the
+	// table and unless it cannot print tables. The table is watched in the background, in
+	// parallel with the objects.
+	tables := a.startTableWatch(kind, namespace)
+	err := a.watchResources(kind.GVR, namespace, kind.Dynamic, func(objects []runtime.Object) func() {
+		table := tables.snapshot()
+		return func() {
+			if table != nil {
+				a.fillServerTable(kind, table, objects)
+			} else {
+				a.fillKindTable(kind, objects)
+			}
+			a.updateFollowed()
+		}
+	})
+	if err != nil {
+		a.stopTableWatch()
+		return err
+	}
+	if tables != nil {
+		tables.onChange(a.resourceWatch.notify)
+	}
+	return nil
+}
+
+// listsNamespaces reports whether the resource table lists kind across namespaces, which adds a
+// NAMESPACE column
+func (a *App) listsNamespaces(kind *ResourceKind) bool {
+	return kind.Namespaced && a.allNamespaces
+}
+
+// selectResource shows the details of an object selected in the resource list
+func (a *App) selectResource(kind *ResourceKind, obj runtime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	a.SelectedResource = accessor.GetName()
+	a.SelectedResourceType = kind.Type
+	a.leaveManifest()
+	a.leaveDiff()
+	a.stopFollowing()
+
+	if kind.Detail != nil {
+		err = kind.Detail(a, obj)
+	} else {
+		err = a.showObjectInfo(kind, obj)
+	}
+	if err != nil {
+		a.showError(fmt.Sprintf("Error showing %s: %v", accessor.GetName(), err))
+	}
+
+	// Update responsive layout after selection
+	if a.grid != nil {
+		a.updateGridLayout(a.grid)
+	}
+}
+
+// GetResourceDisplayName returns a human-readable name for resource types
+func GetResourceDisplayName(resourceType ResourceType) string {
+	if kind, ok := lookupResourceKind(resourceType); ok {
+		return kind.DisplayName
+	}
+	return string(resourceType)
+}
+
+// GetAllResourceTypes returns all supported resource types
+func GetAllResourceTypes() []ResourceType {
+	resourceTypes := make([]ResourceType, 0, len(resourceKinds))
+	for _, kind := range resourceKinds {
+		resourceTypes = append(resourceTypes, kind.Type)
+	}
+	return resourceTypes
+}
+
+// ParseResourceType resolves a resource type from its name, plural, API resource name
+// or display name (case-insensitive)
+func ParseResourceType(name string) (ResourceType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, kind := range resourceKinds {
+		switch name {
+		case string(kind.Type), string(kind.Type) + "s", kind.GVR.Resource, strings.ToLower(kind.DisplayName):
+			return kind.Type, nil
+		}
+	}
+	return "", fmt.Errorf("unknown resource type: %s", name)
+}
+
+// IsNamespacedResourceType reports whether resources of the given type live in a namespace
+func IsNamespacedResourceType(resourceType ResourceType) bool {
+	if kind, ok := lookupResourceKind(resourceType); ok {
+		return kind.Namespaced
+	}
+	return true
+}
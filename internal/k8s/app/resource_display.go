@@ -8,6 +8,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // showDeploymentInfo displays detailed information about a deployment
@@ -293,6 +295,56 @@ func (a *App) showNodeInfo(node *corev1.Node) error {
 	return nil
 }
 
+// showObjectInfo displays the metadata of an object whose kind has no dedicated renderer
+func (a *App) showObjectInfo(kind *ResourceKind, obj runtime.Object) error {
+	var status strings.Builder
+	if err := writeObjectInfo(&status, kind, obj); err != nil {
+		return err
+	}
+
+	a.InfoView.SetText(status.String())
+	return nil
+}
+
+// writeObjectInfo writes the metadata and list columns of an object
+func writeObjectInfo(status *strings.Builder, kind *ResourceKind, obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	status.WriteString(fmt.Sprintf("[green]%s: [white]%s\n", kind.DisplayName, accessor.GetName()))
+	if accessor.GetNamespace() != "" {
+		status.WriteString(fmt.Sprintf("[green]Namespace: [white]%s\n", accessor.GetNamespace()))
+	}
+	status.WriteString(fmt.Sprintf("[green]Age: [white]%s\n", getAge(accessor.GetCreationTimestamp().Time)))
+
+	var row []string
+	if kind.Row != nil {
+		row = kind.Row(obj)
+	}
+	for i, column := range kind.Columns {
+		if column != "AGE" && i < len(row) {
+			status.WriteString(fmt.Sprintf("[green]%s: [white]%s\n", column, row[i]))
+		}
+	}
+
+	if len(accessor.GetLabels()) > 0 {
+		status.WriteString("\n[green]Labels:\n")
+		for k, v := range accessor.GetLabels() {
+			status.WriteString(fmt.Sprintf("  [yellow]%s[white]: %s\n", k, v))
+		}
+	}
+
+	if len(accessor.GetAnnotations()) > 0 {
+		status.WriteString("\n[green]Annotations:\n")
+		for k, v := range accessor.GetAnnotations() {
+			status.WriteString(fmt.Sprintf("  [yellow]%s[white]: %s\n", k, v))
+		}
+	}
+	return nil
+}
+
 // getAge returns a human-readable age string
 func getAge(t time.Time) string {
 	duration := time.Since(t)
@@ -7,64 +7,22 @@ import (
 // showResourceTypeModal displays a modal for selecting resource types
 func (a *App) showResourceTypeModal() {
 	// Create a modal for resource type selection
+	buttons := make([]string, 0, len(resourceKinds)+1)
+	for _, kind := range resourceKinds {
+		buttons = append(buttons, kind.DisplayName)
+	}
+	buttons = append(buttons, "Cancel")
+
 	modal := tview.NewModal().
 		SetText("Select Resource Type").
-		AddButtons([]string{
-			"Deployments",
-			"ReplicaSets",
-			"StatefulSets",
-			"DaemonSets",
-			"Jobs",
-			"CronJobs",
-			"Services",
-			"ConfigMaps",
-			"Secrets",
-			"Ingresses",
-			"NetworkPolicies",
-			"PVCs",
-			"PVs",
-			"ServiceAccounts",
-			"Roles",
-			"RoleBindings",
-			"ClusterRoles",
-			"ClusterRoleBindings",
-			"Endpoints",
-			"HPAs",
-			"LimitRanges",
-			"ResourceQuotas",
-			"Nodes",
-			"Pods",
-			"Namespaces",
-			"Cancel",
-		}).
+		AddButtons(buttons).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			a.pages.HidePage("resource_modal")
-			
-			switch buttonLabel {
-			case "Deployments":
-				a.SelectedResourceType = ResourceTypeDeployment
-				a.LoadDeployments()
-			case "Services":
-				a.SelectedResourceType = ResourceTypeService
-				a.LoadServices()
-			case "ConfigMaps":
-				a.SelectedResourceType = ResourceTypeConfigMap
-				a.LoadConfigMaps()
-			case "Secrets":
-				a.SelectedResourceType = ResourceTypeSecret
-				a.LoadSecrets()
-			case "Ingresses":
-				a.SelectedResourceType = ResourceTypeIngress
-				a.LoadIngresses()
-			case "Nodes":
-				a.SelectedResourceType = ResourceTypeNode
-				a.LoadNodes()
-			case "Pods":
-				a.SelectedResourceType = ResourceTypePod
-				a.LoadPods()
-			case "Namespaces":
-				a.SelectedResourceType = ResourceTypeNamespace
-				a.LoadNamespaces()
+			a.App.SetFocus(a.getCurrentFocus())
+
+			if buttonIndex >= 0 && buttonIndex < len(resourceKinds) {
+				a.SelectedResourceType = resourceKinds[buttonIndex].Type
+				a.loadSelectedResourceType()
 			}
 		})
 
@@ -2,10 +2,14 @@ package app
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // ResourceType represents different Kubernetes resource types
@@ -37,6 +41,7 @@ const (
 	ResourceTypeHPA         ResourceType = "hpa"
 	ResourceTypeLimitRange  ResourceType = "limitrange"
 	ResourceTypeResourceQuota ResourceType = "resourcequota"
+	ResourceTypeEvent       ResourceType = "event"
 )
 
 // ResourceInfo holds information about a Kubernetes resource
@@ -50,340 +55,247 @@ type ResourceInfo struct {
 	Spec      interface{}
 }
 
-// ResourceManager handles loading and managing different resource types
-func (a *App) LoadResources(resourceType ResourceType) error {
-	switch resourceType {
-	case ResourceTypeDeployment:
-		return a.LoadDeployments()
-	case ResourceTypeService:
-		return a.LoadServices()
-	case ResourceTypeConfigMap:
-		return a.LoadConfigMaps()
-	case ResourceTypeSecret:
-		return a.LoadSecrets()
-	case ResourceTypeIngress:
-		return a.LoadIngresses()
-	case ResourceTypeNode:
-		return a.LoadNodes()
-	case ResourceTypeReplicaSet:
-		return a.LoadReplicaSets()
-	case ResourceTypeStatefulSet:
-		return a.LoadStatefulSets()
-	case ResourceTypeDaemonSet:
-		return a.LoadDaemonSets()
-	case ResourceTypeJob:
-		return a.LoadJobs()
-	case ResourceTypeCronJob:
-		return a.LoadCronJobs()
-	case ResourceTypePVC:
-		return a.LoadPVCs()
-	case ResourceTypePV:
-		return a.LoadPVs()
-	case ResourceTypeNetworkPolicy:
-		return a.LoadNetworkPolicies()
-	case ResourceTypeServiceAccount:
-		return a.LoadServiceAccounts()
-	case ResourceTypeRole:
-		return a.LoadRoles()
-	case ResourceTypeRoleBinding:
-		return a.LoadRoleBindings()
-	case ResourceTypeClusterRole:
-		return a.LoadClusterRoles()
-	case ResourceTypeClusterRoleBinding:
-		return a.LoadClusterRoleBindings()
-	case ResourceTypeEndpoint:
-		return a.LoadEndpoints()
-	case ResourceTypeHPA:
-		return a.LoadHPAs()
-	case ResourceTypeLimitRange:
-		return a.LoadLimitRanges()
-	case ResourceTypeResourceQuota:
-		return a.LoadResourceQuotas()
-	default:
-		return fmt.Errorf("unsupported resource type: %s", resourceType)
+// deploymentRow returns the list columns of a deployment
+func deploymentRow(obj runtime.Object) []string {
+	deployment := obj.(*appsv1.Deployment)
+	return []string{
+		fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, replicasOrDefault(deployment.Spec.Replicas)),
+		fmt.Sprint(deployment.Status.UpdatedReplicas),
+		fmt.Sprint(deployment.Status.AvailableReplicas),
+		getAge(deployment.CreationTimestamp.Time),
 	}
 }
 
-// LoadDeployments loads all deployments in the current namespace
-func (a *App) LoadDeployments() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
-	}
-	if a.CurrentNs == "" {
-		return fmt.Errorf("no namespace selected")
+// serviceRow returns the list columns of a service
+func serviceRow(obj runtime.Object) []string {
+	service := obj.(*corev1.Service)
+
+	clusterIP := service.Spec.ClusterIP
+	if clusterIP == "" {
+		clusterIP = "None"
 	}
 
-	deployments, err := a.KubeClient.AppsV1().Deployments(a.CurrentNs).List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing deployments: %v", err)
+	var externalIPs []string
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			externalIPs = append(externalIPs, ingress.IP)
+		} else if ingress.Hostname != "" {
+			externalIPs = append(externalIPs, ingress.Hostname)
+		}
+	}
+	externalIPs = append(externalIPs, service.Spec.ExternalIPs...)
+
+	var ports []string
+	for _, port := range service.Spec.Ports {
+		if port.NodePort != 0 {
+			ports = append(ports, fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, port.Protocol))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
 	}
 
-	a.ResourceList.Clear()
-	for _, deployment := range deployments.Items {
-		deployment := deployment // capture for closure
-		readyReplicas := fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, *deployment.Spec.Replicas)
-		
-		a.ResourceList.AddItem(deployment.Name, readyReplicas, 0, func() {
-			a.SelectedResource = deployment.Name
-			a.SelectedResourceType = ResourceTypeDeployment
-			a.showDeploymentInfo(&deployment)
-		})
+	return []string{
+		string(service.Spec.Type),
+		clusterIP,
+		joinOrNone(externalIPs),
+		joinOrNone(ports),
+		getAge(service.CreationTimestamp.Time),
 	}
+}
 
-	return nil
+// configMapRow returns the list columns of a configmap
+func configMapRow(obj runtime.Object) []string {
+	configMap := obj.(*corev1.ConfigMap)
+	return []string{
+		fmt.Sprint(len(configMap.Data) + len(configMap.BinaryData)),
+		getAge(configMap.CreationTimestamp.Time),
+	}
 }
 
-// LoadServices loads all services in the current namespace
-func (a *App) LoadServices() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
+// secretRow returns the list columns of a secret
+func secretRow(obj runtime.Object) []string {
+	secret := obj.(*corev1.Secret)
+	return []string{
+		string(secret.Type),
+		fmt.Sprint(len(secret.Data)),
+		getAge(secret.CreationTimestamp.Time),
 	}
-	if a.CurrentNs == "" {
-		return fmt.Errorf("no namespace selected")
+}
+
+// ingressRow returns the list columns of an ingress
+func ingressRow(obj runtime.Object) []string {
+	ingress := obj.(*netv1.Ingress)
+
+	class := "<none>"
+	if ingress.Spec.IngressClassName != nil {
+		class = *ingress.Spec.IngressClassName
 	}
 
-	services, err := a.KubeClient.CoreV1().Services(a.CurrentNs).List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing services: %v", err)
+	var hosts []string
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, rule.Host)
+		}
 	}
 
-	a.ResourceList.Clear()
-	for _, service := range services.Items {
-		service := service // capture for closure
-		
-		typeStr := string(service.Spec.Type)
-		clusterIP := service.Spec.ClusterIP
-		if clusterIP == "" {
-			clusterIP = "None"
+	var addresses []string
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
 		}
-		
-		info := fmt.Sprintf("%s %s", typeStr, clusterIP)
-		
-		a.ResourceList.AddItem(service.Name, info, 0, func() {
-			a.SelectedResource = service.Name
-			a.SelectedResourceType = ResourceTypeService
-			a.showServiceInfo(&service)
-		})
 	}
 
-	return nil
+	hostInfo := "*"
+	if len(hosts) > 0 {
+		hostInfo = strings.Join(hosts, ",")
+	}
+
+	return []string{class, hostInfo, strings.Join(addresses, ","), getAge(ingress.CreationTimestamp.Time)}
 }
 
-// LoadConfigMaps loads all configmaps in the current namespace
-func (a *App) LoadConfigMaps() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
+// nodeRow returns the list columns of a node
+func nodeRow(obj runtime.Object) []string {
+	node := obj.(*corev1.Node)
+
+	status := "Unknown"
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			status = "NotReady"
+			if condition.Status == corev1.ConditionTrue {
+				status = "Ready"
+			}
+			break
+		}
 	}
-	if a.CurrentNs == "" {
-		return fmt.Errorf("no namespace selected")
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
 	}
 
-	configMaps, err := a.KubeClient.CoreV1().ConfigMaps(a.CurrentNs).List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing configmaps: %v", err)
+	var roles []string
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			roles = append(roles, role)
+		}
 	}
+	sort.Strings(roles)
 
-	a.ResourceList.Clear()
-	for _, cm := range configMaps.Items {
-		cm := cm // capture for closure
-		
-		dataCount := fmt.Sprintf("%d keys", len(cm.Data))
-		
-		a.ResourceList.AddItem(cm.Name, dataCount, 0, func() {
-			a.SelectedResource = cm.Name
-			a.SelectedResourceType = ResourceTypeConfigMap
-			a.showConfigMapInfo(&cm)
-		})
+	return []string{
+		status,
+		joinOrNone(roles),
+		getAge(node.CreationTimestamp.Time),
+		node.Status.NodeInfo.KubeletVersion,
 	}
-
-	return nil
 }
 
-// LoadSecrets loads all secrets in the current namespace
-func (a *App) LoadSecrets() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
-	}
-	if a.CurrentNs == "" {
-		return fmt.Errorf("no namespace selected")
-	}
+// podRow returns the list columns of a pod
+func podRow(obj runtime.Object) []string {
+	pod := obj.(*corev1.Pod)
 
-	secrets, err := a.KubeClient.CoreV1().Secrets(a.CurrentNs).List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing secrets: %v", err)
+	ready := 0
+	restarts := int32(0)
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		restarts += cs.RestartCount
 	}
 
-	a.ResourceList.Clear()
-	for _, secret := range secrets.Items {
-		secret := secret // capture for closure
-		
-		typeStr := string(secret.Type)
-		dataCount := fmt.Sprintf("%d keys", len(secret.Data))
-		
-		a.ResourceList.AddItem(secret.Name, fmt.Sprintf("%s %s", typeStr, dataCount), 0, func() {
-			a.SelectedResource = secret.Name
-			a.SelectedResourceType = ResourceTypeSecret
-			a.showSecretInfo(&secret)
-		})
+	return []string{
+		fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		podStatus(pod),
+		fmt.Sprint(restarts),
+		getAge(pod.CreationTimestamp.Time),
+		pod.Status.PodIP,
+		pod.Spec.NodeName,
 	}
-
-	return nil
 }
 
-// LoadIngresses loads all ingresses in the current namespace
-func (a *App) LoadIngresses() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
-	}
-	if a.CurrentNs == "" {
-		return fmt.Errorf("no namespace selected")
+// podStatus returns the status of a pod the way kubectl reports it, preferring
+// container waiting and termination reasons over the pod phase
+func podStatus(pod *corev1.Pod) string {
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
 	}
 
-	ingresses, err := a.KubeClient.NetworkingV1().Ingresses(a.CurrentNs).List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing ingresses: %v", err)
+	for i, cs := range pod.Status.InitContainerStatuses {
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+			continue
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason != "":
+			return "Init:" + cs.State.Terminated.Reason
+		case cs.State.Terminated != nil:
+			return fmt.Sprintf("Init:ExitCode:%d", cs.State.Terminated.ExitCode)
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing":
+			return "Init:" + cs.State.Waiting.Reason
+		default:
+			return fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
 	}
 
-	a.ResourceList.Clear()
-	for _, ingress := range ingresses.Items {
-		ingress := ingress // capture for closure
-		
-		var hosts []string
-		for _, rule := range ingress.Spec.Rules {
-			if rule.Host != "" {
-				hosts = append(hosts, rule.Host)
-			}
+	for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+		cs := pod.Status.ContainerStatuses[i]
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			reason = cs.State.Waiting.Reason
+		} else if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			reason = cs.State.Terminated.Reason
+		} else if cs.State.Terminated != nil {
+			reason = fmt.Sprintf("ExitCode:%d", cs.State.Terminated.ExitCode)
 		}
-		
-		hostInfo := "No hosts"
-		if len(hosts) > 0 {
-			hostInfo = strings.Join(hosts, ", ")
-		}
-		
-		a.ResourceList.AddItem(ingress.Name, hostInfo, 0, func() {
-			a.SelectedResource = ingress.Name
-			a.SelectedResourceType = ResourceTypeIngress
-			a.showIngressInfo(&ingress)
-		})
 	}
 
-	return nil
+	if pod.DeletionTimestamp != nil {
+		reason = "Terminating"
+	}
+	return reason
 }
 
-// LoadNodes loads all nodes in the cluster
-func (a *App) LoadNodes() error {
-	if a.KubeClient == nil {
-		return fmt.Errorf("kubernetes client not initialized")
+// containerState returns the state of a container as a short word with its reason
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil && state.Waiting.Reason != "":
+		return "Waiting: " + state.Waiting.Reason
+	case state.Waiting != nil:
+		return "Waiting"
+	case state.Terminated != nil && state.Terminated.Reason != "":
+		return "Terminated: " + state.Terminated.Reason
+	case state.Terminated != nil:
+		return "Terminated"
 	}
+	return "<unknown>"
+}
 
-	nodes, err := a.KubeClient.CoreV1().Nodes().List(a.getContext(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("error listing nodes: %v", err)
-	}
+// namespaceRow returns the list columns of a namespace
+func namespaceRow(obj runtime.Object) []string {
+	ns := obj.(*corev1.Namespace)
+	return []string{string(ns.Status.Phase), getAge(ns.CreationTimestamp.Time)}
+}
 
-	a.ResourceList.Clear()
-	for _, node := range nodes.Items {
-		node := node // capture for closure
-		
-		status := "Ready"
-		for _, condition := range node.Status.Conditions {
-			if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue {
-				status = "NotReady"
-				break
-			}
-		}
-		
-		info := fmt.Sprintf("%s %s", node.Status.NodeInfo.KubeletVersion, status)
-		
-		a.ResourceList.AddItem(node.Name, info, 0, func() {
-			a.SelectedResource = node.Name
-			a.SelectedResourceType = ResourceTypeNode
-			a.showNodeInfo(&node)
-		})
+// ageRow returns the age column of any object
+func ageRow(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return []string{""}
 	}
-
-	return nil
+	return []string{getAge(accessor.GetCreationTimestamp().Time)}
 }
 
-// GetResourceDisplayName returns a human-readable name for resource types
-func GetResourceDisplayName(resourceType ResourceType) string {
-	switch resourceType {
-	case ResourceTypeDeployment:
-		return "Deployments"
-	case ResourceTypeReplicaSet:
-		return "ReplicaSets"
-	case ResourceTypeStatefulSet:
-		return "StatefulSets"
-	case ResourceTypeDaemonSet:
-		return "DaemonSets"
-	case ResourceTypeJob:
-		return "Jobs"
-	case ResourceTypeCronJob:
-		return "CronJobs"
-	case ResourceTypeService:
-		return "Services"
-	case ResourceTypeConfigMap:
-		return "ConfigMaps"
-	case ResourceTypeSecret:
-		return "Secrets"
-	case ResourceTypeIngress:
-		return "Ingresses"
-	case ResourceTypeNetworkPolicy:
-		return "NetworkPolicies"
-	case ResourceTypePVC:
-		return "PersistentVolumeClaims"
-	case ResourceTypePV:
-		return "PersistentVolumes"
-	case ResourceTypeServiceAccount:
-		return "ServiceAccounts"
-	case ResourceTypeRole:
-		return "Roles"
-	case ResourceTypeRoleBinding:
-		return "RoleBindings"
-	case ResourceTypeClusterRole:
-		return "ClusterRoles"
-	case ResourceTypeClusterRoleBinding:
-		return "ClusterRoleBindings"
-	case ResourceTypeEndpoint:
-		return "Endpoints"
-	case ResourceTypeHPA:
-		return "HorizontalPodAutoscalers"
-	case ResourceTypeLimitRange:
-		return "LimitRanges"
-	case ResourceTypeResourceQuota:
-		return "ResourceQuotas"
-	case ResourceTypeNode:
-		return "Nodes"
-	default:
-		return string(resourceType)
+// replicasOrDefault dereferences a replica count, which defaults to 1 when unset
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
 	}
+	return *replicas
 }
 
-// GetAllResourceTypes returns all supported resource types
-func GetAllResourceTypes() []ResourceType {
-	return []ResourceType{
-		ResourceTypeDeployment,
-		ResourceTypeReplicaSet,
-		ResourceTypeStatefulSet,
-		ResourceTypeDaemonSet,
-		ResourceTypeJob,
-		ResourceTypeCronJob,
-		ResourceTypeService,
-		ResourceTypeConfigMap,
-		ResourceTypeSecret,
-		ResourceTypeIngress,
-		ResourceTypeNetworkPolicy,
-		ResourceTypePVC,
-		ResourceTypePV,
-		ResourceTypeServiceAccount,
-		ResourceTypeRole,
-		ResourceTypeRoleBinding,
-		ResourceTypeClusterRole,
-		ResourceTypeClusterRoleBinding,
-		ResourceTypeEndpoint,
-		ResourceTypeHPA,
-		ResourceTypeLimitRange,
-		ResourceTypeResourceQuota,
-		ResourceTypeNode,
+// joinOrNone joins values with commas, or returns <none> when there are none
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
 	}
+	return strings.Join(values, ",")
 }
@@ -1,53 +1,41 @@
 package app
 
+import "fmt"
+
 // initResourceTypes initializes the resource type selection
 func (a *App) initResourceTypes() {
-	// Add resource types to the ResourceTypeList
-	resourceTypes := GetAllResourceTypes()
-	
 	a.ResourceTypeList.Clear()
-	
-	// Add each resource type
-	for _, resourceType := range resourceTypes {
-		rt := resourceType // capture for closure
-		a.ResourceTypeList.AddItem(GetResourceDisplayName(resourceType), "", 0, func() {
-			a.SelectedResourceType = rt
+
+	// Add each registered resource type
+	for _, kind := range resourceKinds {
+		a.ResourceTypeList.AddItem(kind.DisplayName, "", 0, func() {
+			a.SelectedResourceType = kind.Type
+			a.loadSelectedResourceType()
+		})
+	}
+
+	// Discovered resources follow, under a header per API group
+	group := ""
+	for i, kind := range a.discoveredKinds {
+		if i == 0 || kind.GVR.Group != group {
+			group = kind.GVR.Group
+			header := group
+			if header == "" {
+				header = "core"
+			}
+			a.ResourceTypeList.AddItem(fmt.Sprintf("[::b]%s", header), "", 0, nil)
+		}
+		a.ResourceTypeList.AddItem("  "+kind.DisplayName, "", 0, func() {
+			a.SelectedResourceType = kind.Type
 			a.loadSelectedResourceType()
 		})
 	}
-	
-	// Add "Pods" as an option to switch back to pod view
-	a.ResourceTypeList.AddItem("Pods", "", 0, func() {
-		a.SelectedResourceType = ResourceTypePod
-		a.LoadPods()
-	})
-	
-	// Add "Namespaces" as an option
-	a.ResourceTypeList.AddItem("Namespaces", "", 0, func() {
-		a.SelectedResourceType = ResourceTypeNamespace
-		a.LoadNamespaces()
-	})
 }
 
 // loadSelectedResourceType loads the selected resource type
 func (a *App) loadSelectedResourceType() {
-	switch a.SelectedResourceType {
-	case ResourceTypeDeployment:
-		a.LoadDeployments()
-	case ResourceTypeService:
-		a.LoadServices()
-	case ResourceTypeConfigMap:
-		a.LoadConfigMaps()
-	case ResourceTypeSecret:
-		a.LoadSecrets()
-	case ResourceTypeIngress:
-		a.LoadIngresses()
-	case ResourceTypeNode:
-		a.LoadNodes()
-	case ResourceTypePod:
-		a.LoadPods()
-	case ResourceTypeNamespace:
-		a.LoadNamespaces()
+	if err := a.LoadResources(a.SelectedResourceType); err != nil {
+		a.showError(fmt.Sprintf("Error loading %s: %v", GetResourceDisplayName(a.SelectedResourceType), err))
 	}
 }
 
@@ -63,14 +51,14 @@ func (a *App) updateGridLayoutForResources() {
 			AddItem(a.ResourceTypeList, 0, 1, 1, 1, 0, 0, false).
 			AddItem(a.ResourceList, 0, 2, 1, 1, 0, 0, false).
 			AddItem(a.InfoView, 1, 0, 1, 2, 0, 0, false).
-			AddItem(a.LogsView, 1, 2, 1, 1, 0, 0, false)
+			AddItem(a.logsPane, 1, 2, 1, 1, 0, 0, false)
 	} else {
 		// Default layout - 3 column layout
 		a.grid.AddItem(a.NsList, 0, 0, 1, 1, 0, 0, true).
 			AddItem(a.ResourceTypeList, 0, 1, 1, 1, 0, 0, false).
 			AddItem(a.ResourceList, 0, 2, 1, 1, 0, 0, false).
 			AddItem(a.InfoView, 1, 0, 1, 2, 0, 0, false).
-			AddItem(a.LogsView, 1, 2, 1, 1, 0, 0, false)
+			AddItem(a.logsPane, 1, 2, 1, 1, 0, 0, false)
 	}
 	
 	a.updateGridLayout(a.grid)
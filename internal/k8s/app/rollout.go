@@ -0,0 +1,512 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Annotations kubectl uses for rollouts
+const (
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// defaultProgressDeadline is how long a rollout may take when its workload sets no deadline,
+// as for deployments
+const defaultProgressDeadline = 600 * time.Second
+
+// rolloutRevision is a revision of a workload in its rollout history
+type rolloutRevision struct {
+	number      int64
+	changeCause string
+	images      []string
+	created     time.Time
+	current     bool
+	template    *corev1.PodTemplateSpec
+	patch       []byte // Strategic merge patch restoring a controller revision
+}
+
+// showRolloutMenu lists the rollout actions of the workload highlighted in the resource table
+func (a *App) showRolloutMenu() {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return
+	}
+	deployment, isDeployment := obj.(*appsv1.Deployment)
+	switch obj.(type) {
+	case *appsv1.Deployment, *appsv1.StatefulSet, *appsv1.DaemonSet:
+	default:
+		a.showError("Rollouts are only available for deployments, statefulsets and daemonsets")
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" Rollout %s/%s ", kind.Type, tview.Escape(accessor.GetName())))
+	run := func(action func()) func() {
+		return func() {
+			a.closeRolloutMenu()
+			action()
+		}
+	}
+	list.AddItem("Status", "Follow the rollout until it completes", 's', run(func() { a.followRollout(kind, obj) }))
+	list.AddItem("Restart", "Replace every pod, like kubectl rollout restart", 'r', run(func() {
+		a.guardDestructive("Restart", fmt.Sprintf("Replace every pod of %s %s?", kind.DisplayName, accessor.GetName()),
+			accessor.GetName(), func() { a.restartRollout(kind, obj) })
+	}))
+	if isDeployment {
+		if deployment.Spec.Paused {
+			list.AddItem("Resume", "Resume the paused rollout", 'p', run(func() { a.setRolloutPaused(kind, deployment, false) }))
+		} else {
+			list.AddItem("Pause", "Stop rolling out changes to the pod template", 'p', run(func() { a.setRolloutPaused(kind, deployment, true) }))
+		}
+	}
+	list.AddItem("History", "List the revisions and roll back to one of them", 'h', run(func() { a.showRolloutHistory(kind, obj) }))
+	list.SetDoneFunc(a.closeRolloutMenu)
+
+	a.pages.AddPage("rollout", modalFrame(list, 60, 2*list.GetItemCount()+2), true, true)
+	a.App.SetFocus(list)
+}
+
+// closeRolloutMenu removes the rollout menu and restores focus
+func (a *App) closeRolloutMenu() {
+	a.pages.RemovePage("rollout")
+	a.App.SetFocus(a.getCurrentFocus())
+}
+
+// restartRollout replaces the pods of a workload by changing the restartedAt annotation of its
+// pod template, then follows the rollout
+func (a *App) restartRollout(kind *ResourceKind, obj runtime.Object) {
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	updated, err := a.patchWorkload(kind, obj, types.StrategicMergePatchType, []byte(patch))
+	if err != nil {
+		a.showError(fmt.Sprintf("Error restarting rollout: %v", err))
+		return
+	}
+	a.showStatus(fmt.Sprintf("Restarted %s/%s", kind.Type, objectName(obj)))
+	a.followRollout(kind, updated)
+}
+
+// setRolloutPaused pauses or resumes the rollout of a deployment
+func (a *App) setRolloutPaused(kind *ResourceKind, deployment *appsv1.Deployment, paused bool) {
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	if _, err := a.patchWorkload(kind, deployment, types.MergePatchType, []byte(patch)); err != nil {
+		a.showError(fmt.Sprintf("Error updating rollout: %v", err))
+		return
+	}
+	if paused {
+		a.showStatus(fmt.Sprintf("Paused the rollout of deployment/%s", deployment.Name))
+	} else {
+		a.showStatus(fmt.Sprintf("Resumed the rollout of deployment/%s", deployment.Name))
+	}
+}
+
+// patchWorkload patches a deployment, statefulset or daemonset and returns the result
+func (a *App) patchWorkload(kind *ResourceKind, obj runtime.Object, patchType types.PatchType, patch []byte) (runtime.Object, error) {
+	if a.KubeClient == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, namespace, name := a.getContext(), accessor.GetNamespace(), accessor.GetName()
+	opts := metav1.PatchOptions{FieldManager: fieldManager}
+	apps := a.KubeClient.AppsV1()
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		return apps.Deployments(namespace).Patch(ctx, name, patchType, patch, opts)
+	case *appsv1.StatefulSet:
+		return apps.StatefulSets(namespace).Patch(ctx, name, patchType, patch, opts)
+	case *appsv1.DaemonSet:
+		return apps.DaemonSets(namespace).Patch(ctx, name, patchType, patch, opts)
+	}
+	return nil, fmt.Errorf("%s have no rollouts", kind.DisplayName)
+}
+
+// rolloutHistory returns the revisions of a workload, newest first. Deployment revisions are
+// the ReplicaSets it owns; statefulset and daemonset revisions are their ControllerRevisions.
+func (a *App) rolloutHistory(obj runtime.Object) ([]rolloutRevision, error) {
+	if a.KubeClient == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := podSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{LabelSelector: selector.String()}
+	ctx, namespace := a.getContext(), accessor.GetNamespace()
+
+	var revisions []rolloutRevision
+	if deployment, ok := obj.(*appsv1.Deployment); ok {
+		list, err := a.KubeClient.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error listing replicasets: %v", err)
+		}
+		current := deployment.Annotations[revisionAnnotation]
+		for i := range list.Items {
+			rs := &list.Items[i]
+			if !ownedBy(rs, deployment.UID) {
+				continue
+			}
+			number, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+			if err != nil {
+				continue
+			}
+			revisions = append(revisions, rolloutRevision{
+				number:      number,
+				changeCause: rs.Annotations[changeCauseAnnotation],
+				images:      containerImages(rs.Spec.Template.Spec),
+				created:     rs.CreationTimestamp.Time,
+				current:     rs.Annotations[revisionAnnotation] == current,
+				template:    rs.Spec.Template.DeepCopy(),
+			})
+		}
+	} else {
+		list, err := a.KubeClient.AppsV1().ControllerRevisions(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error listing controller revisions: %v", err)
+		}
+		for i := range list.Items {
+			cr := &list.Items[i]
+			if !ownedBy(cr, accessor.GetUID()) {
+				continue
+			}
+			template := revisionTemplate(cr.Data.Raw)
+			revisions = append(revisions, rolloutRevision{
+				number:      cr.Revision,
+				changeCause: cr.Annotations[changeCauseAnnotation],
+				images:      containerImages(template.Spec),
+				created:     cr.CreationTimestamp.Time,
+				template:    template,
+				patch:       cr.Data.Raw,
+			})
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].number > revisions[j].number })
+	if _, ok := obj.(*appsv1.Deployment); !ok && len(revisions) > 0 {
+		// The newest controller revision is the one the pods are being rolled out to
+		revisions[0].current = true
+	}
+	return revisions, nil
+}
+
+// ownedBy reports whether the controller of an object has the given UID
+func ownedBy(obj metav1.Object, uid types.UID) bool {
+	owner := metav1.GetControllerOf(obj)
+	return owner != nil && owner.UID == uid
+}
+
+// containerImages returns the images of the containers of a pod spec
+func containerImages(spec corev1.PodSpec) []string {
+	images := make([]string, 0, len(spec.Containers))
+	for _, c := range spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// revisionTemplate returns the pod template saved in a controller revision
+func revisionTemplate(data []byte) *corev1.PodTemplateSpec {
+	var revision struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	_ = json.Unmarshal(data, &revision)
+	return &revision.Spec.Template
+}
+
+// showRolloutHistory lists the revisions of a workload. Selecting one rolls back to it, and d
+// compares it with the current revision, or with the revision marked with Space.
+func (a *App) showRolloutHistory(kind *ResourceKind, obj runtime.Object) {
+	revisions, err := a.rolloutHistory(obj)
+	if err != nil {
+		a.showError(fmt.Sprintf("Error getting rollout history: %v", err))
+		return
+	}
+	if len(revisions) == 0 {
+		a.showError(fmt.Sprintf("%s/%s has no rollout history", kind.Type, objectName(obj)))
+		return
+	}
+
+	list := tview.NewList()
+	keys := "[::b]Enter[::-] roll back, [::b]Space[::-] mark, [::b]D[::-] diff, [::b]Esc[::-] close"
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" History of %s/%s (%s) ", kind.Type, tview.Escape(objectName(obj)), keys))
+	marked := -1
+	revisionText := func(i int) string {
+		text := fmt.Sprintf("Revision %d", revisions[i].number)
+		if revisions[i].current {
+			text = "[green]" + text + " (current)"
+		}
+		if i == marked {
+			text = "[yellow]* " + text
+		}
+		return text
+	}
+	for i, revision := range revisions {
+		mainText := revisionText(i)
+		cause := revision.changeCause
+		if cause == "" {
+			cause = "<none>"
+		}
+		info := fmt.Sprintf("%s | %s | %s ago", cause, strings.Join(revision.images, ", "), getAge(revision.created))
+		list.AddItem(mainText, tview.Escape(info), 0, func() {
+			if revision.current {
+				return
+			}
+			a.closeRolloutHistory()
+			a.confirmDestructive("Roll back",
+				fmt.Sprintf("Roll %s %s back to revision %d?", kind.DisplayName, objectName(obj), revision.number),
+				objectName(obj), func() { a.undoRollout(kind, obj, revision) })
+		})
+	}
+	list.SetDoneFunc(a.closeRolloutHistory)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case 'q', 'Q':
+			a.closeRolloutHistory()
+		case ' ':
+			previous := marked
+			if marked = list.GetCurrentItem(); marked == previous {
+				marked = -1
+			}
+			for _, i := range []int{previous, list.GetCurrentItem()} {
+				if i >= 0 {
+					_, secondary := list.GetItemText(i)
+					list.SetItemText(i, revisionText(i), secondary)
+				}
+			}
+		case 'd', 'D':
+			other := marked
+			if other < 0 {
+				for i, revision := range revisions {
+					if revision.current {
+						other = i
+					}
+				}
+			}
+			selected := list.GetCurrentItem()
+			if other < 0 || other == selected {
+				return nil
+			}
+			from, to := revisions[max(other, selected)], revisions[min(other, selected)]
+			a.closeRolloutHistory()
+			a.diffRevisions(kind, objectName(obj), from, to)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	a.pages.AddPage("history", modalFrame(list, 100, min(2*len(revisions)+2, 24)), true, true)
+	a.App.SetFocus(list)
+}
+
+// closeRolloutHistory removes the rollout history and restores focus
+func (a *App) closeRolloutHistory() {
+	a.pages.RemovePage("history")
+	a.App.SetFocus(a.getCurrentFocus())
+}
+
+// undoRollout rolls a workload back to a revision, as kubectl rollout undo does, then follows
+// the rollout
+func (a *App) undoRollout(kind *ResourceKind, obj runtime.Object, revision rolloutRevision) {
+	var updated runtime.Object
+	var err error
+	if _, ok := obj.(*appsv1.Deployment); ok {
+		// The pod-template-hash label is added by the deployment controller to its replicasets
+		template := revision.template.DeepCopy()
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+		var patch []byte
+		patch, err = json.Marshal([]map[string]interface{}{
+			{"op": "replace", "path": "/spec/template", "value": template},
+		})
+		if err == nil {
+			updated, err = a.patchWorkload(kind, obj, types.JSONPatchType, patch)
+		}
+	} else {
+		updated, err = a.patchWorkload(kind, obj, types.StrategicMergePatchType, revision.patch)
+	}
+	if err != nil {
+		a.showError(fmt.Sprintf("Error rolling back: %v", err))
+		return
+	}
+	a.showStatus(fmt.Sprintf("Rolled %s/%s back to revision %d", kind.Type, objectName(obj), revision.number))
+	a.followRollout(kind, updated)
+}
+
+// followRollout shows the rollout status of a workload in InfoView until it completes, fails or
+// exceeds its progress deadline
+func (a *App) followRollout(kind *ResourceKind, obj runtime.Object) {
+	started := time.Now()
+	a.follow(kind, obj, "Rollout "+objectName(obj), func(obj runtime.Object) bool {
+		return a.renderRolloutStatus(kind, obj, started)
+	})
+}
+
+// renderRolloutStatus shows the rollout status of a workload, reporting whether the rollout is
+// over
+func (a *App) renderRolloutStatus(kind *ResourceKind, obj runtime.Object, started time.Time) bool {
+	status := rolloutStatus(obj)
+	elapsed := time.Since(started).Round(time.Second)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[green]Rollout: [white]%s/%s\n", kind.Type, tview.Escape(objectName(obj))))
+	b.WriteString(fmt.Sprintf("[green]Updated: [white]%s %d/%d\n", progressBar(status.updated, status.desired, scaleProgressWidth), status.updated, status.desired))
+	b.WriteString(fmt.Sprintf("[green]Ready: [white]%s %d/%d\n", progressBar(status.ready, status.desired, scaleProgressWidth), status.ready, status.desired))
+	b.WriteString(fmt.Sprintf("[green]Available: [white]%s %d/%d\n", progressBar(status.available, status.desired, scaleProgressWidth), status.available, status.desired))
+	b.WriteString("\n")
+
+	over := true
+	switch {
+	case status.failed:
+		b.WriteString("[red]" + tview.Escape(status.message))
+	case status.done:
+		b.WriteString(fmt.Sprintf("[green]%s (%s)", tview.Escape(status.message), elapsed))
+	case elapsed > status.deadline:
+		b.WriteString(fmt.Sprintf("[red]Rollout exceeded its progress deadline of %s: %s", status.deadline, tview.Escape(status.message)))
+	default:
+		b.WriteString(fmt.Sprintf("[yellow]%s (%s)", tview.Escape(status.message), elapsed))
+		over = false
+	}
+	a.InfoView.SetText(b.String())
+	return over
+}
+
+// workloadRollout is the rollout status of a workload
+type workloadRollout struct {
+	desired, updated, ready, available int32
+	message                            string
+	done                               bool
+	failed                             bool
+	deadline                           time.Duration
+}
+
+// rolloutStatus computes the rollout status of a workload the way kubectl rollout status does
+func rolloutStatus(obj runtime.Object) workloadRollout {
+	s := workloadRollout{deadline: defaultProgressDeadline}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		s.desired = 1
+		if o.Spec.Replicas != nil {
+			s.desired = *o.Spec.Replicas
+		}
+		s.updated, s.ready, s.available = o.Status.UpdatedReplicas, o.Status.ReadyReplicas, o.Status.AvailableReplicas
+		if o.Spec.ProgressDeadlineSeconds != nil {
+			s.deadline = time.Duration(*o.Spec.ProgressDeadlineSeconds) * time.Second
+		}
+		switch {
+		case o.Generation > o.Status.ObservedGeneration:
+			s.message = "Waiting for the deployment spec update to be observed..."
+		case deploymentDeadlineExceeded(o):
+			s.message, s.failed = fmt.Sprintf("Deployment %s exceeded its progress deadline", o.Name), true
+		case o.Spec.Paused:
+			s.message = "Rollout is paused"
+		case s.updated < s.desired:
+			s.message = fmt.Sprintf("Waiting for rollout to finish: %d out of %d new replicas have been updated...", s.updated, s.desired)
+		case o.Status.Replicas > s.updated:
+			s.message = fmt.Sprintf("Waiting for rollout to finish: %d old replicas are pending termination...", o.Status.Replicas-s.updated)
+		case s.available < s.updated:
+			s.message = fmt.Sprintf("Waiting for rollout to finish: %d of %d updated replicas are available...", s.available, s.updated)
+		default:
+			s.message, s.done = fmt.Sprintf("Deployment %s successfully rolled out", o.Name), true
+		}
+	case *appsv1.StatefulSet:
+		s.desired = 1
+		if o.Spec.Replicas != nil {
+			s.desired = *o.Spec.Replicas
+		}
+		s.updated, s.ready, s.available = o.Status.UpdatedReplicas, o.Status.ReadyReplicas, o.Status.AvailableReplicas
+		switch {
+		case o.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType:
+			s.message, s.failed = "Rollout status is only available for the RollingUpdate strategy", true
+		case o.Generation > o.Status.ObservedGeneration:
+			s.message = "Waiting for the statefulset spec update to be observed..."
+		case s.ready < s.desired:
+			s.message = fmt.Sprintf("Waiting for %d pods to be ready...", s.desired-s.ready)
+		case o.Status.UpdateRevision != o.Status.CurrentRevision && !statefulSetPartitioned(o):
+			s.message = fmt.Sprintf("Waiting for the rolling update to complete: %d pods at revision %s...", s.updated, o.Status.UpdateRevision)
+		default:
+			s.message, s.done = fmt.Sprintf("StatefulSet %s successfully rolled out", o.Name), true
+		}
+	case *appsv1.DaemonSet:
+		s.desired = o.Status.DesiredNumberScheduled
+		s.updated, s.ready, s.available = o.Status.UpdatedNumberScheduled, o.Status.NumberReady, o.Status.NumberAvailable
+		switch {
+		case o.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType:
+			s.message, s.failed = "Rollout status is only available for the RollingUpdate strategy", true
+		case o.Generation > o.Status.ObservedGeneration:
+			s.message = "Waiting for the daemonset spec update to be observed..."
+		case s.updated < s.desired:
+			s.message = fmt.Sprintf("Waiting for rollout to finish: %d out of %d new pods have been updated...", s.updated, s.desired)
+		case s.available < s.desired:
+			s.message = fmt.Sprintf("Waiting for rollout to finish: %d of %d updated pods are available...", s.available, s.desired)
+		default:
+			s.message, s.done = fmt.Sprintf("DaemonSet %s successfully rolled out", o.Name), true
+		}
+	default:
+		s.message, s.failed = "No rollout status", true
+	}
+	return s
+}
+
+// deploymentDeadlineExceeded reports whether the deployment controller gave up on a rollout
+func deploymentDeadlineExceeded(deployment *appsv1.Deployment) bool {
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing {
+			return c.Reason == "ProgressDeadlineExceeded"
+		}
+	}
+	return false
+}
+
+// statefulSetPartitioned reports whether a statefulset has updated every pod above its
+// rolling update partition, which completes a partitioned rollout
+func statefulSetPartitioned(sts *appsv1.StatefulSet) bool {
+	rolling := sts.Spec.UpdateStrategy.RollingUpdate
+	if rolling == nil || rolling.Partition == nil || *rolling.Partition == 0 {
+		return false
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return sts.Status.UpdatedReplicas >= replicas-*rolling.Partition
+}
+
+// objectName returns the name of an object
+func objectName(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
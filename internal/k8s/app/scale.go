@@ -0,0 +1,232 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// previousReplicasAnnotation records the replicas of a workload scaled to zero, to restore them
+const previousReplicasAnnotation = "k8stui/previous-replicas"
+
+// scaleProgressWidth is the width of the progress bar of a scaling workload
+const scaleProgressWidth = 30
+
+// workloadReplicas returns the desired, current and ready replicas of a scalable workload
+func workloadReplicas(obj runtime.Object) (desired, current, ready int32, ok bool) {
+	var spec *int32
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		spec, current, ready = o.Spec.Replicas, o.Status.Replicas, o.Status.ReadyReplicas
+	case *appsv1.StatefulSet:
+		spec, current, ready = o.Spec.Replicas, o.Status.Replicas, o.Status.ReadyReplicas
+	case *appsv1.ReplicaSet:
+		spec, current, ready = o.Spec.Replicas, o.Status.Replicas, o.Status.ReadyReplicas
+	default:
+		return 0, 0, 0, false
+	}
+	desired = 1
+	if spec != nil {
+		desired = *spec
+	}
+	return desired, current, ready, true
+}
+
+// selectedScalable returns the workload highlighted in the resource table if it can be scaled
+func (a *App) selectedScalable() (*ResourceKind, runtime.Object, int32, bool) {
+	kind, obj, ok := a.selectedObject()
+	if !ok {
+		return nil, nil, 0, false
+	}
+	replicas, _, _, ok := workloadReplicas(obj)
+	if !ok {
+		a.showError("Only deployments, statefulsets and replicasets can be scaled")
+		return nil, nil, 0, false
+	}
+	return kind, obj, replicas, true
+}
+
+// showScalePrompt asks for the replicas of the workload highlighted in the resource table
+func (a *App) showScalePrompt() {
+	kind, obj, replicas, ok := a.selectedScalable()
+	if !ok {
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	label := fmt.Sprintf("scale %s/%s to: ", kind.Type, accessor.GetName())
+	a.showPrompt(label, fmt.Sprint(replicas), func(text string) {
+		n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 32)
+		if err != nil || n < 0 {
+			a.showError(fmt.Sprintf("Invalid number of replicas: %q", text))
+			return
+		}
+		scale := func() {
+			if err := a.scaleWorkload(kind, obj, int32(n)); err != nil {
+				a.showError(fmt.Sprintf("Error scaling %s: %v", accessor.GetName(), err))
+			}
+		}
+		if n == 0 && replicas > 0 {
+			a.guardDestructive("Scale to zero", fmt.Sprintf("Scale %s %s from %d replicas to zero?", kind.DisplayName, accessor.GetName(), replicas),
+				accessor.GetName(), scale)
+			return
+		}
+		scale()
+	})
+}
+
+// toggleScaleToZero scales the highlighted workload to zero, recording its replicas in an
+// annotation, or restores the recorded replicas of a workload scaled to zero
+func (a *App) toggleScaleToZero() {
+	kind, obj, replicas, ok := a.selectedScalable()
+	if !ok {
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	if replicas > 0 {
+		a.guardDestructive("Scale to zero", fmt.Sprintf("Scale %s %s from %d replicas to zero?", kind.DisplayName, accessor.GetName(), replicas),
+			accessor.GetName(), func() {
+				if err := a.annotatePreviousReplicas(kind, accessor, fmt.Sprint(replicas)); err != nil {
+					a.showError(fmt.Sprintf("Error recording the replicas of %s: %v", accessor.GetName(), err))
+					return
+				}
+				if err := a.scaleWorkload(kind, obj, 0); err != nil {
+					a.showError(fmt.Sprintf("Error scaling %s: %v", accessor.GetName(), err))
+				}
+			})
+		return
+	}
+
+	previous, err := strconv.ParseInt(accessor.GetAnnotations()[previousReplicasAnnotation], 10, 32)
+	if err != nil || previous <= 0 {
+		a.showError(fmt.Sprintf("%s has no previous replica count recorded; press S to scale it", accessor.GetName()))
+		return
+	}
+	if err := a.scaleWorkload(kind, obj, int32(previous)); err != nil {
+		a.showError(fmt.Sprintf("Error scaling %s: %v", accessor.GetName(), err))
+		return
+	}
+	if err := a.annotatePreviousReplicas(kind, accessor, nil); err != nil {
+		a.showError(fmt.Sprintf("Error removing the replicas recorded on %s: %v", accessor.GetName(), err))
+	}
+}
+
+// annotatePreviousReplicas sets the previous replicas annotation of a workload, or removes it
+// when value is nil
+func (a *App) annotatePreviousReplicas(kind *ResourceKind, accessor metav1.Object, value interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{previousReplicasAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, namespace, name := a.getContext(), accessor.GetNamespace(), accessor.GetName()
+	apps := a.KubeClient.AppsV1()
+	switch kind.Type {
+	case ResourceTypeDeployment:
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	case ResourceTypeStatefulSet:
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	case ResourceTypeReplicaSet:
+		_, err = apps.ReplicaSets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	default:
+		return fmt.Errorf("%s cannot be scaled", kind.DisplayName)
+	}
+	return err
+}
+
+// scaleWorkload sets the replicas of a workload through its scale subresource and follows
+// the ready replicas in InfoView
+func (a *App) scaleWorkload(kind *ResourceKind, obj runtime.Object, replicas int32) error {
+	if a.KubeClient == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	from, _, _, _ := workloadReplicas(obj)
+
+	ctx, namespace, name := a.getContext(), accessor.GetNamespace(), accessor.GetName()
+	scale := &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+	}
+	opts := metav1.UpdateOptions{FieldManager: fieldManager}
+	apps := a.KubeClient.AppsV1()
+	switch kind.Type {
+	case ResourceTypeDeployment:
+		_, err = apps.Deployments(namespace).UpdateScale(ctx, name, scale, opts)
+	case ResourceTypeStatefulSet:
+		_, err = apps.StatefulSets(namespace).UpdateScale(ctx, name, scale, opts)
+	case ResourceTypeReplicaSet:
+		_, err = apps.ReplicaSets(namespace).UpdateScale(ctx, name, scale, opts)
+	default:
+		return fmt.Errorf("%s cannot be scaled", kind.DisplayName)
+	}
+	if err != nil {
+		return err
+	}
+
+	a.showStatus(fmt.Sprintf("Scaling %s/%s from %d to %d replicas", kind.Type, name, from, replicas))
+	started := time.Now()
+	a.follow(kind, obj, "Scale "+name, func(obj runtime.Object) bool {
+		return a.renderScaleProgress(kind, obj, from, replicas, started)
+	})
+	return nil
+}
+
+// renderScaleProgress shows the ready replicas of a workload converging from one count to
+// another, reporting whether they have
+func (a *App) renderScaleProgress(kind *ResourceKind, obj runtime.Object, from, to int32, started time.Time) bool {
+	desired, current, ready, ok := workloadReplicas(obj)
+	accessor, err := meta.Accessor(obj)
+	if !ok || err != nil {
+		return true
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[green]Scaling: [white]%s/%s\n", kind.Type, tview.Escape(accessor.GetName())))
+	b.WriteString(fmt.Sprintf("[green]Replicas: [white]%d → %d\n", from, to))
+	b.WriteString(fmt.Sprintf("[green]Ready: [white]%s %d/%d\n", progressBar(ready, to, scaleProgressWidth), ready, to))
+	b.WriteString(fmt.Sprintf("[green]Current: [white]%d\n", current))
+
+	elapsed := time.Since(started).Round(time.Second)
+	done := desired == to && current == to && ready == to
+	if done {
+		b.WriteString(fmt.Sprintf("\n[green]Scaled to %d replicas in %s", to, elapsed))
+	} else {
+		b.WriteString(fmt.Sprintf("\n[yellow]Waiting for replicas... (%s)", elapsed))
+	}
+	a.InfoView.SetText(b.String())
+	return done
+}
+
+// progressBar draws a bar of width cells filled in proportion to done out of total
+func progressBar(done, total int32, width int) string {
+	filled := width
+	if total > 0 {
+		filled = int(min(done, total)) * width / int(total)
+	}
+	return "[green]" + strings.Repeat("█", filled) + "[gray]" + strings.Repeat("░", width-filled) + "[white]"
+}
@@ -0,0 +1,407 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+// tableAcceptHeader asks the API server to print lists as a meta.k8s.io Table, as kubectl get
+// does, falling back to plain JSON on servers that cannot
+const tableAcceptHeader = "application/json;as=Table;v=1;g=meta.k8s.io,application/json"
+
+// Server table watches retry after an error with a delay doubling up to tableWatchMaxRetry
+const (
+	tableWatchRetry    = 2 * time.Second
+	tableWatchMaxRetry = time.Minute
+)
+
+// tableWatch keeps the table the API server prints for the resources of a kind up to date. It
+// lists them once and then watches them with as=Table, as kubectl get -w does, so that changes
+// cost no more than the informer's own watch and rendering never waits for the network.
+type tableWatch struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	stopCh    chan struct{}
+
+	mu      sync.Mutex
+	columns []metav1.TableColumnDefinition
+	rows    map[string]metav1.TableRow // By namespace/name
+	synced  bool                       // The table was listed
+	changed func()                     // Called when the table changes, off the UI goroutine
+}
+
+// tableClient returns the REST client server tables are requested with, or nil for the fake
+// clientsets, which cannot print tables
+func (a *App) tableClient() rest.Interface {
+	if a.KubeClient == nil {
+		return nil
+	}
+	client, ok := a.KubeClient.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok || client == nil {
+		return nil
+	}
+	return client
+}
+
+// listServerTable lists the resources of gvr in namespace as a server-printed table, or
+// returns nil when the server answers a plain list
+func (a *App) listServerTable(ctx context.Context, client rest.Interface, gvr schema.GroupVersionResource, namespace string) (*metav1.Table, error) {
+	raw, err := client.Get().
+		AbsPath(resourcePath(gvr, namespace)).
+		SetHeader("Accept", tableAcceptHeader).
+		Param("includeObject", string(metav1.IncludeMetadata)).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %v", gvr.Resource, err)
+	}
+
+	var table metav1.Table
+	if err := json.Unmarshal(raw, &table); err != nil {
+		return nil, fmt.Errorf("error decoding %s table: %v", gvr.Resource, err)
+	}
+	if table.Kind != "Table" {
+		a.markServerTableUnsupported(gvr)
+		return nil, nil
+	}
+	return &table, nil
+}
+
+// startTableWatch returns the watch of the server table of kind in namespace, starting it
+// unless it already runs. It returns nil when the server cannot print the kind.
+func (a *App) startTableWatch(kind *ResourceKind, namespace string) *tableWatch {
+	if t := a.tableWatch; t != nil && t.gvr == kind.GVR && t.namespace == namespace {
+		return t
+	}
+	a.stopTableWatch()
+	client := a.tableClient()
+	if client == nil || !a.serverTableSupported(kind.GVR) {
+		return nil
+	}
+
+	t := &tableWatch{gvr: kind.GVR, namespace: namespace, stopCh: make(chan struct{})}
+	a.tableWatch = t
+	go a.runTableWatch(client, t)
+	return t
+}
+
+// stopTableWatch stops the watch of the server table, if any
+func (a *App) stopTableWatch() {
+	if a.tableWatch != nil {
+		close(a.tableWatch.stopCh)
+		a.tableWatch = nil
+	}
+}
+
+// runTableWatch lists and watches the server table of t until it is stopped, listing again
+// when the server ends the watch and, with a growing delay, after errors such as an expired
+// resource version
+func (a *App) runTableWatch(client rest.Interface, t *tableWatch) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-t.stopCh
+		cancel()
+	}()
+
+	retry := tableWatchRetry
+	for {
+		listed, err := a.watchServerTable(ctx, client, t)
+		if err == nil && !listed {
+			return // The server does not print this kind
+		}
+		if listed && errors.Is(err, io.EOF) {
+			retry = tableWatchRetry
+		}
+		select {
+		case <-t.stopCh:
+			return
+		case <-time.After(retry):
+		}
+		retry = min(2*retry, tableWatchMaxRetry)
+	}
+}
+
+// watchServerTable lists the server table of t, then applies the changes the server reports
+// until the watch ends. It reports whether the table was listed.
+func (a *App) watchServerTable(ctx context.Context, client rest.Interface, t *tableWatch) (bool, error) {
+	table, err := a.listServerTable(ctx, client, t.gvr, t.namespace)
+	if err != nil || table == nil {
+		return false, err
+	}
+	t.reset(table)
+
+	stream, err := client.Get().
+		AbsPath(resourcePath(t.gvr, t.namespace)).
+		SetHeader("Accept", tableAcceptHeader).
+		Param("includeObject", string(metav1.IncludeMetadata)).
+		Param("watch", "true").
+		Param("resourceVersion", table.ResourceVersion).
+		Stream(ctx)
+	if err != nil {
+		return true, fmt.Errorf("error watching %s: %v", t.gvr.Resource, err)
+	}
+	defer stream.Close()
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var event metav1.WatchEvent
+		if err := decoder.Decode(&event); err != nil {
+			return true, err
+		}
+		if event.Type == string(watch.Error) {
+			return true, fmt.Errorf("error watching %s: %s", t.gvr.Resource, event.Object.Raw)
+		}
+		var change metav1.Table
+		if err := json.Unmarshal(event.Object.Raw, &change); err != nil {
+			return true, fmt.Errorf("error decoding %s table: %v", t.gvr.Resource, err)
+		}
+		t.apply(watch.EventType(event.Type), change.Rows)
+	}
+}
+
+// onChange sets what is called when the table of t changes, calling it at once when the table
+// is already listed, so that a view rendered before then catches up
+func (t *tableWatch) onChange(changed func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.changed = changed
+	if t.synced {
+		changed()
+	}
+}
+
+// notifyChanged calls the change callback of t with its lock held
+func (t *tableWatch) notifyChanged() {
+	if t.changed != nil {
+		t.changed()
+	}
+}
+
+// reset replaces the contents of t with a listed table
+func (t *tableWatch) reset(table *metav1.Table) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.columns = table.ColumnDefinitions
+	t.rows = make(map[string]metav1.TableRow, len(table.Rows))
+	for _, row := range table.Rows {
+		if key, ok := tableRowKey(row); ok {
+			t.rows[key] = row
+		}
+	}
+	t.synced = true
+	t.notifyChanged()
+}
+
+// apply applies a watch event to the rows of t
+func (t *tableWatch) apply(eventType watch.EventType, rows []metav1.TableRow) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	changed := false
+	for _, row := range rows {
+		key, ok := tableRowKey(row)
+		if !ok {
+			continue
+		}
+		switch eventType {
+		case watch.Added, watch.Modified:
+			t.rows[key] = row
+			changed = true
+		case watch.Deleted:
+			delete(t.rows, key)
+			changed = true
+		}
+	}
+	if changed {
+		t.notifyChanged()
+	}
+}
+
+// snapshot returns the current server table of t sorted by namespace and name, or nil until
+// it is listed. It is safe to call on a nil watch.
+func (t *tableWatch) snapshot() *metav1.Table {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.synced {
+		return nil
+	}
+	keys := make([]string, 0, len(t.rows))
+	for key := range t.rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	table := &metav1.Table{ColumnDefinitions: t.columns, Rows: make([]metav1.TableRow, 0, len(keys))}
+	for _, key := range keys {
+		table.Rows = append(table.Rows, t.rows[key])
+	}
+	return table
+}
+
+// tableRowKey returns the namespace/name of the object printed in a row
+func tableRowKey(row metav1.TableRow) (string, bool) {
+	var metadata metav1.PartialObjectMetadata
+	if err := json.Unmarshal(row.Object.Raw, &metadata); err != nil || metadata.Name == "" {
+		return "", false
+	}
+	return metadata.Namespace + "/" + metadata.Name, true
+}
+
+// serverTableSupported reports whether the server may print tables for gvr
+func (a *App) serverTableSupported(gvr schema.GroupVersionResource) bool {
+	a.serverTableMu.Lock()
+	defer a.serverTableMu.Unlock()
+	return !a.serverTableUnsupported[gvr]
+}
+
+// markServerTableUnsupported records that the server answers plain lists for gvr
+func (a *App) markServerTableUnsupported(gvr schema.GroupVersionResource) {
+	a.serverTableMu.Lock()
+	defer a.serverTableMu.Unlock()
+	if a.serverTableUnsupported == nil {
+		a.serverTableUnsupported = make(map[schema.GroupVersionResource]bool)
+	}
+	a.serverTableUnsupported[gvr] = true
+}
+
+// resourcePath returns the API path listing gvr in namespace, or in every namespace when empty
+func resourcePath(gvr schema.GroupVersionResource, namespace string) string {
+	parts := []string{"/apis", gvr.Group, gvr.Version}
+	if gvr.Group == "" {
+		parts = []string{"/api", gvr.Version}
+	}
+	if namespace != "" {
+		parts = append(parts, "namespaces", namespace)
+	}
+	return path.Join(append(parts, gvr.Resource)...)
+}
+
+// fillServerTable shows a server-printed table in the resource table. Rows are matched to the
+// watched objects by namespace and name so that selecting a row opens the live object.
+func (a *App) fillServerTable(kind *ResourceKind, table *metav1.Table, objects []runtime.Object) {
+	byKey := make(map[string]runtime.Object, len(objects))
+	for _, obj := range objects {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			byKey[accessor.GetNamespace()+"/"+accessor.GetName()] = obj
+		}
+	}
+
+	// Priority 0 columns are the default ones; the others are only printed with -o wide
+	var columns []int
+	var headers []string
+	for i, column := range table.ColumnDefinitions {
+		if column.Priority == 0 || a.wideColumns {
+			columns = append(columns, i)
+			headers = append(headers, strings.ToUpper(column.Name))
+		}
+	}
+	withNamespace := a.listsNamespaces(kind)
+	if withNamespace {
+		headers = withNamespaceColumn(headers, "NAMESPACE")
+	}
+	a.ResourceList.SetColumns(headers)
+
+	a.ResourceList.ClearRows()
+	for _, row := range table.Rows {
+		var metadata metav1.PartialObjectMetadata
+		if err := json.Unmarshal(row.Object.Raw, &metadata); err != nil {
+			continue
+		}
+		key := metadata.Namespace + "/" + metadata.Name
+
+		cells := make([]string, 0, len(columns))
+		for _, i := range columns {
+			cells = append(cells, formatTableCell(row.Cells, i))
+		}
+		if withNamespace {
+			cells = withNamespaceColumn(cells, metadata.Namespace)
+		}
+
+		var selected func()
+		if obj, ok := byKey[key]; ok {
+			selected = func() { a.selectResource(kind, obj) }
+		}
+		a.ResourceList.AddRow(key, cells, metadata.Labels, selected)
+	}
+	a.ResourceList.Render()
+}
+
+// fillKindTable shows objects in the resource table using the columns built for their kind
+func (a *App) fillKindTable(kind *ResourceKind, objects []runtime.Object) {
+	withNamespace := a.listsNamespaces(kind)
+	columns := append([]string{"NAME"}, kind.Columns...)
+	if withNamespace {
+		columns = withNamespaceColumn(columns, "NAMESPACE")
+	}
+	a.ResourceList.SetColumns(columns)
+
+	a.ResourceList.ClearRows()
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		key := accessor.GetNamespace() + "/" + accessor.GetName()
+		cells := append([]string{accessor.GetName()}, kind.Row(obj)...)
+		if withNamespace {
+			cells = withNamespaceColumn(cells, accessor.GetNamespace())
+		}
+		a.ResourceList.AddRow(key, cells, accessor.GetLabels(), func() {
+			a.selectResource(kind, obj)
+		})
+	}
+	a.ResourceList.Render()
+}
+
+// withNamespaceColumn inserts a namespace cell after the name, which stays first for filtering
+func withNamespaceColumn(cells []string, namespace string) []string {
+	return append([]string{cells[0], namespace}, cells[1:]...)
+}
+
+// formatTableCell formats a cell of a server-printed table the way kubectl prints it
+func formatTableCell(cells []interface{}, i int) string {
+	if i >= len(cells) || cells[i] == nil {
+		return "<none>"
+	}
+	switch v := cells[i].(type) {
+	case float64:
+		// JSON numbers decode as float64, but table columns hold integers
+		if v == float64(int64(v)) {
+			return fmt.Sprint(int64(v))
+		}
+		return fmt.Sprint(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, part := range v {
+			parts = append(parts, fmt.Sprint(part))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(cells[i])
+}
+
+// toggleWideColumns shows or hides the columns kubectl only prints with -o wide
+func (a *App) toggleWideColumns() {
+	a.wideColumns = !a.wideColumns
+	if a.viewingContainers || a.SelectedResourceType == "" {
+		return
+	}
+	a.loadSelectedResourceType()
+}
@@ -0,0 +1,323 @@
+package app
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// minColumnWidth is the narrowest a column is shrunk to when the table does not fit its pane
+const minColumnWidth = 4
+
+// tableRow is a row of a ResourceTable and the action run when it is selected
+type tableRow struct {
+	key      string
+	cells    []string
+	labels   map[string]string
+	color    tcell.Color // Text color of the row; the default color leaves it unchanged
+	selected func()
+}
+
+// ResourceTable is a table of resources with a header row, sortable on any column
+type ResourceTable struct {
+	*tview.Table
+	columns    []string
+	rows       []tableRow // Rows added since the last ClearRows
+	shown      []tableRow // Rows as last rendered, in display order
+	sortColumn int
+	sortDesc   bool
+	filter     *listFilter // Rows not matching are hidden; nil shows every row
+	width      int         // Inner width of the pane, used to shrink columns that do not fit
+	selectKey  string      // Row to highlight once it is rendered
+}
+
+// NewResourceTable creates an empty resource table
+func NewResourceTable() *ResourceTable {
+	t := &ResourceTable{Table: tview.NewTable()}
+	t.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSeparator(' ')
+	t.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(t.shown) && t.shown[row-1].selected != nil {
+			t.shown[row-1].selected()
+		}
+	})
+	return t
+}
+
+// SetColumns sets the column headers. Sorting is reset when the columns change.
+func (t *ResourceTable) SetColumns(columns []string) {
+	if strings.Join(columns, "\x00") != strings.Join(t.columns, "\x00") {
+		t.sortColumn = 0
+		t.sortDesc = false
+	}
+	t.columns = columns
+}
+
+// ClearRows starts a new set of rows, which replaces the shown rows on the next Render
+func (t *ResourceTable) ClearRows() {
+	t.rows = nil
+}
+
+// Clear removes every row from the table
+func (t *ResourceTable) Clear() {
+	t.rows = nil
+	t.Render()
+}
+
+// AddRow adds a row identified by key, which keeps it selected across re-renders.
+// The labels are matched by label selector filters.
+func (t *ResourceTable) AddRow(key string, cells []string, labels map[string]string, selected func()) {
+	t.rows = append(t.rows, tableRow{key: key, cells: cells, labels: labels, selected: selected})
+}
+
+// AddColoredRow adds a row like AddRow, drawn in color
+func (t *ResourceTable) AddColoredRow(key string, cells []string, labels map[string]string, color tcell.Color, selected func()) {
+	t.rows = append(t.rows, tableRow{key: key, cells: cells, labels: labels, color: color, selected: selected})
+}
+
+// SelectKey highlights the row identified by key, now or when it is first rendered
+func (t *ResourceTable) SelectKey(key string) {
+	t.selectKey = key
+	t.Render()
+}
+
+// SetFilter hides the rows that do not match f from the next Render
+func (t *ResourceTable) SetFilter(f *listFilter) {
+	t.filter = f
+}
+
+// ItemCount returns the number of rows, excluding the header
+func (t *ResourceTable) ItemCount() int {
+	return len(t.shown)
+}
+
+// Item returns the cells of the row at index in display order
+func (t *ResourceTable) Item(index int) []string {
+	if index < 0 || index >= len(t.shown) {
+		return nil
+	}
+	return t.shown[index].cells
+}
+
+// SelectedKey returns the key of the highlighted row, or "" when the table is empty
+func (t *ResourceTable) SelectedKey() string {
+	row, _ := t.GetSelection()
+	if row < 1 || row > len(t.shown) {
+		return ""
+	}
+	return t.shown[row-1].key
+}
+
+// SortBy sorts on column, reversing the order when it is already the sort column
+func (t *ResourceTable) SortBy(column int) {
+	if column < 0 || column >= len(t.columns) {
+		return
+	}
+	if column == t.sortColumn {
+		t.sortDesc = !t.sortDesc
+	} else {
+		t.sortColumn = column
+		t.sortDesc = false
+	}
+	t.Render()
+}
+
+// handleSortKey sorts the table by the column matching a digit key, starting at 1
+func (t *ResourceTable) handleSortKey(r rune) bool {
+	if r < '1' || r > '9' {
+		return false
+	}
+	t.SortBy(int(r - '1'))
+	return true
+}
+
+// FitWidth shrinks the widest columns so that the table fits in width cells
+func (t *ResourceTable) FitWidth(width int) {
+	if width == t.width {
+		return
+	}
+	t.width = width
+	t.applyWidths()
+}
+
+// Render rebuilds the table cells from the rows in sort order, keeping the highlighted
+// row and scroll position
+func (t *ResourceTable) Render() {
+	selected := t.SelectedKey()
+	if t.selectKey != "" {
+		selected = t.selectKey
+	}
+	offset, _ := t.GetOffset()
+
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := cellAt(t.rows[i].cells, t.sortColumn), cellAt(t.rows[j].cells, t.sortColumn)
+		if t.sortDesc {
+			return compareCells(b, a) < 0
+		}
+		return compareCells(a, b) < 0
+	})
+
+	// Status terms match the STATUS column, or the REASON column of events
+	statusColumn := -1
+	for c, column := range t.columns {
+		if column == "STATUS" || (column == "REASON" && statusColumn < 0) {
+			statusColumn = c
+		}
+	}
+
+	t.shown = nil
+	var matches [][]int
+	for _, row := range t.rows {
+		positions, ok := t.filter.match(cellAt(row.cells, 0), row.labels, cellAt(row.cells, statusColumn))
+		if ok {
+			t.shown = append(t.shown, row)
+			matches = append(matches, positions)
+		}
+	}
+
+	t.Table.Clear()
+	for c, column := range t.columns {
+		header := column
+		if c == t.sortColumn && t.sortDesc {
+			header += "↓"
+		} else if c == t.sortColumn {
+			header += "↑"
+		}
+		t.SetCell(0, c, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	selectedRow := 1
+	for r, row := range t.shown {
+		t.SetCell(r+1, 0, tview.NewTableCell(highlightMatches(cellAt(row.cells, 0), matches[r])))
+		for c := 1; c < len(t.columns); c++ {
+			t.SetCell(r+1, c, tview.NewTableCell(tview.Escape(cellAt(row.cells, c))))
+		}
+		if row.color != tcell.ColorDefault {
+			for c := 0; c < len(t.columns); c++ {
+				t.GetCell(r+1, c).SetTextColor(row.color)
+			}
+		}
+		if row.key == selected {
+			selectedRow = r + 1
+			t.selectKey = ""
+		}
+	}
+	t.applyWidths()
+
+	t.SetOffset(offset, 0)
+	if len(t.shown) > 0 {
+		t.Select(selectedRow, 0)
+	}
+}
+
+// applyWidths caps column widths to fit the pane, shrinking the widest column first
+func (t *ResourceTable) applyWidths() {
+	widths := make([]int, len(t.columns))
+	for c, column := range t.columns {
+		widths[c] = tview.TaggedStringWidth(column) + 1
+		for _, row := range t.shown {
+			widths[c] = max(widths[c], tview.TaggedStringWidth(cellAt(row.cells, c)))
+		}
+	}
+
+	// Columns are separated by one cell
+	if t.width > 0 {
+		for total(widths)+len(widths)-1 > t.width {
+			widest := 0
+			for c := range widths {
+				if widths[c] > widths[widest] {
+					widest = c
+				}
+			}
+			if widths[widest] <= minColumnWidth {
+				break
+			}
+			widths[widest]--
+		}
+	}
+
+	for c := range t.columns {
+		for r := 0; r < t.GetRowCount(); r++ {
+			if cell := t.GetCell(r, c); cell != nil {
+				cell.SetMaxWidth(widths[c])
+			}
+		}
+	}
+}
+
+// cellAt returns the cell at column of a row, or "" when the row is short
+func cellAt(cells []string, column int) string {
+	if column >= 0 && column < len(cells) {
+		return cells[column]
+	}
+	return ""
+}
+
+// total returns the sum of widths
+func total(widths []int) int {
+	sum := 0
+	for _, w := range widths {
+		sum += w
+	}
+	return sum
+}
+
+// compareCells orders two cells, numerically when both hold numbers, ages or ready counts
+func compareCells(a, b string) int {
+	x, okA := cellNumber(a)
+	y, okB := cellNumber(b)
+	if okA && okB {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// ageUnits converts the age units printed by getAge and kubectl to seconds
+var ageUnits = map[string]float64{"mo": 30 * 86400, "y": 365 * 86400, "d": 86400, "h": 3600, "m": 60, "s": 1}
+
+// ageRegexp matches one number and unit of an age such as "5d3h"; "mo" precedes "m"
+var ageRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)?)(mo|y|d|h|m|s)`)
+
+// cellNumber parses cells such as "3", "3 (5m ago)", "2/3", "5d" and "2y10d" as numbers
+func cellNumber(cell string) (float64, bool) {
+	cell = strings.TrimSpace(cell)
+	if i := strings.IndexAny(cell, " /"); i > 0 {
+		cell = cell[:i]
+	}
+	if cell == "" {
+		return 0, false
+	}
+	if n, err := strconv.ParseFloat(cell, 64); err == nil {
+		return n, true
+	}
+	return ageSeconds(cell)
+}
+
+// ageSeconds parses an age made of one or more numbers and units, such as "3m20s"
+func ageSeconds(age string) (float64, bool) {
+	seconds := 0.0
+	for age != "" {
+		m := ageRegexp.FindStringSubmatch(age)
+		if m == nil {
+			return 0, false
+		}
+		n, _ := strconv.ParseFloat(m[1], 64)
+		seconds += n * ageUnits[m[2]]
+		age = age[len(m[0]):]
+	}
+	return seconds, true
+}
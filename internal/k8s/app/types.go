@@ -2,13 +2,30 @@ package app
 
 import (
 	"io"
+	"sync"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/rivo/tview"
 )
 
+// Options holds the startup configuration of the application
+type Options struct {
+	Kubeconfig string       // Path to a kubeconfig file; $KUBECONFIG and ~/.kube/config are used when empty
+	Context    string       // Kubeconfig context to use instead of the current context
+	Namespace  string       // Namespace to open at startup
+	Resource   ResourceType // Resource type to open at startup
+	ReadOnly   bool         // Refuse every action that modifies the cluster
+	ConfigFile string       // Path to the config file; DefaultConfigPath is used when empty
+	LogDir     string       // Directory logs are saved under, overriding the config file
+	GzipLogs   bool         // Compress saved logs
+}
+
 // App represents the main application
 type App struct {
 	App                  *tview.Application `json:"-"` // JSON tag "-" to avoid circular reference
@@ -16,15 +33,25 @@ type App struct {
 	grid                 *tview.Grid        // Main grid layout for responsive updates
 	NsList               *tview.List
 
-	ResourceList         *tview.List       // New list for other resource types
+	ResourceList         *ResourceTable    // Table of the resources of the selected type
 	ResourceTypeList     *tview.List       // List to select resource type
 	InfoView             *tview.TextView
 	LogsView             *tview.TextView
+	logsPane             *tview.Flex     // LogsView above its options bar
+	logOptionsBar        *tview.TextView // Shows the log options and their keys
+	logOptions           logOptions      // Options of the log stream shown in LogsView
+	logBuffer            *logBuffer      // Lines of the log stream shown in LogsView
+	logView              logView         // Pause, search and filters of LogsView
+	logSource            string          // What LogsView shows the logs of
+	aggregator           *logAggregator  // Streams the logs of every pod of a workload, if any
 	KubeClient           kubernetes.Interface
+	DynamicClient        dynamic.Interface
 	RestConfig           *rest.Config
+	CurrentContext       string
 	CurrentNs            string
 	SelectedNs           string
 	SelectedPod          string
+	SelectedPodNs        string // Namespace of SelectedPod, which differs from CurrentNs across all namespaces
 	SelectedCont         string
 	SelectedResource     string
 	SelectedResourceType ResourceType
@@ -32,4 +59,33 @@ type App struct {
 	stopChan             chan struct{}
 	logStream            io.ReadCloser
 	logStopChan          chan struct{}
+	viewingContainers    bool // ResourceList currently shows the containers of SelectedPod
+	ReadOnly             bool // Set by the -readonly flag; see isReadOnly
+	options              Options
+	loadingRules         *clientcmd.ClientConfigLoadingRules
+	contextStates        map[string]ContextState // Last view of every visited context
+	resourceWatch        *resourceWatch          // Informer feeding ResourceList
+	namespaceWatch       *resourceWatch          // Informer feeding NsList
+	discoveredKinds      []*ResourceKind         // Resource types found through discovery that are not in the registry
+	wideColumns          bool                    // Show the columns kubectl prints with -o wide
+	nsFilter             *listFilter             // Filter typed in NsList
+	resourceListTitle    string                  // Title of ResourceList without its filter
+	mainFlex             *tview.Flex             // Holds the grid and, while filtering, the filter bar
+	manifest             *manifestView           // Manifest shown in InfoView, if any
+	StatusBar            *tview.TextView         // One-line outcome of the last action
+	editor               func(path string) error // Replaces $EDITOR, for tests
+	exec                 func(containerName string, command []string) error // Replaces ExecInContainer, for tests
+	updates         chan func()                                        // Receives the UI updates of background work instead of QueueUpdateDraw, for tests
+	allNamespaces        bool                    // Resources of every namespace are listed
+	config               Config                  // Contents of the config file
+	logDir               string                  // Directory logs are saved under
+	followed             *followedObject         // Object whose progress is shown in InfoView
+	diff                 *diffView               // Diff shown in InfoView, if any
+	portForwards         []*portForward          // Forwards opened, kept across views until stopped
+	portForwardsRefresh  chan struct{}           // Closed to stop refreshing the open port forward panel
+	forwardDialer        func(namespace, pod string) (httpstream.Dialer, error) // Replaces the SPDY dialer, for tests
+
+	serverTableMu          sync.Mutex
+	serverTableUnsupported map[schema.GroupVersionResource]bool // Resources the server does not print as tables
+	tableWatch             *tableWatch                          // Server table of the resource list
 }
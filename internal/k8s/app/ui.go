@@ -1,6 +1,9 @@
 package app
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -11,27 +14,36 @@ func (a *App) initUI() {
 	a.NsList.SetBorder(true).SetTitle(" Namespaces ")
 	a.ResourceTypeList.SetBorder(true).SetTitle(" Resource Types ")
 	a.ResourceList.SetBorder(true).SetTitle(" Resources ")
+	a.ResourceList.SetInputCapture(a.handleResourceListKey)
 	
 	// Configure InfoView with scrolling
 	a.InfoView.SetBorder(true).SetTitle(" Info ")
+	a.InfoView.SetRegions(true)
+	a.InfoView.SetInputCapture(a.handleInfoKey)
 	a.InfoView.SetChangedFunc(func() {
 		a.App.Draw()
 	})
 
-	// Configure LogsView with auto-scrolling
+	// Configure LogsView; renderLogs scrolls it to the end or to the current search match
 	a.LogsView.SetBorder(true).SetTitle(" Logs ")
+	a.LogsView.SetRegions(true)
 	a.LogsView.SetChangedFunc(func() {
-		a.LogsView.ScrollToEnd()
 		a.App.Draw()
 	})
+	a.LogsView.SetInputCapture(a.handleLogsKey)
+	a.logOptionsBar = tview.NewTextView().SetDynamicColors(true)
+	a.logsPane = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.LogsView, 0, 1, false).
+		AddItem(a.logOptionsBar, 1, 0, false)
+	a.renderLogOptions()
 
 	// Initialize resource type selection
 	a.initResourceTypes()
 
 	// Create the main grid layout
 	a.grid = tview.NewGrid()
-	hotkeyHelp := "[::b]TAB/Shift+TAB[::-] Navigate | [::b]ENTER[::-] Select | [::b]Ctrl+D[::-] Delete | [::b]Q[::-] Quit | [::b]↑/↓/←/→[::-] Scroll | [::b]Ctrl+R[::-] Resource Types"
-	a.grid.SetBorder(true).SetTitle(" K8s TUI - " + hotkeyHelp + " ")
+	a.grid.SetBorder(true)
+	a.updateTitle()
 
 	// Set up the grid layout to be responsive to terminal size
 	a.grid.SetRows(0, 0). // Two rows of equal height
@@ -42,7 +54,7 @@ func (a *App) initUI() {
 		AddItem(a.ResourceTypeList, 0, 1, 1, 1, 0, 0, false).
 		AddItem(a.ResourceList, 0, 2, 1, 1, 0, 0, false).
 		AddItem(a.InfoView, 1, 0, 1, 3, 0, 0, false).
-		AddItem(a.LogsView, 1, 2, 1, 1, 0, 0, false)
+		AddItem(a.logsPane, 1, 2, 1, 1, 0, 0, false)
 
 	// Set up dynamic column sizes based on terminal width
 	a.updateGridLayout(a.grid)
@@ -56,11 +68,12 @@ func (a *App) initUI() {
 	})
 
 	// Create a pages container that will hold our main UI and modals
-	mainFlex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(a.grid, 0, 1, true)
+	a.mainFlex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.grid, 0, 1, true).
+		AddItem(a.StatusBar, 1, 0, false)
 
 	// Add the main UI to the pages
-	a.pages.AddPage("main", mainFlex, true, true)
+	a.pages.AddPage("main", a.mainFlex, true, true)
 
 	// Set up the application with pages as root
 	a.App.SetRoot(a.pages, true).
@@ -68,6 +81,14 @@ func (a *App) initUI() {
 
 	// Set up key bindings
 	a.App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Text inputs receive every key, so typing does not trigger hotkeys
+		if _, ok := a.App.GetFocus().(*tview.InputField); ok {
+			return event
+		}
+		if a.refuseMutation(event) {
+			return nil
+		}
+
 		switch event.Key() {
 		case tcell.KeyTab, tcell.KeyBacktab:
 			// Handle tab navigation
@@ -80,7 +101,14 @@ func (a *App) initUI() {
 			// Show resource type selection
 			a.showResourceTypeModal()
 			return nil
+		case tcell.KeyCtrlP:
+			a.showPortForwards()
+			return nil
 		case tcell.KeyRune:
+			// Modals such as the context picker handle their own letters, q closing them
+			if front, _ := a.pages.GetFrontPage(); front != "main" {
+				return event
+			}
 			switch event.Rune() {
 			case 'q', 'Q':
 				a.App.Stop()
@@ -89,6 +117,13 @@ func (a *App) initUI() {
 				// Show resource type selection
 				a.showResourceTypeModal()
 				return nil
+			case 'c', 'C':
+				// Show kube context selection
+				a.showContextPicker()
+				return nil
+			case '/':
+				a.showFilter()
+				return nil
 			}
 		}
 
@@ -96,36 +131,132 @@ func (a *App) initUI() {
 	})
 }
 
+// updateTitle shows the active context and the hotkey help in the main frame title
+func (a *App) updateTitle() {
+	readOnly := a.isReadOnly()
+	var help []string
+	for _, key := range hotkeys {
+		if !readOnly || key.mutation == "" {
+			help = append(help, fmt.Sprintf("[::b]%s[::-] %s", key.keys, key.label))
+		}
+	}
+	title := " K8s TUI "
+	borderColor, bordersColor := tview.Styles.BorderColor, tview.Styles.GraphicsColor
+	switch {
+	case a.isProtected():
+		// Nobody should mistake a protected cluster for another one
+		title += fmt.Sprintf("[white:red:b] PROTECTED: %s [-:-:-] ", tview.Escape(a.CurrentContext))
+		borderColor, bordersColor = protectedColor, protectedColor
+	case a.CurrentContext != "":
+		title += tview.Escape("["+a.CurrentContext+"]") + " "
+	}
+	a.grid.SetBorderColor(borderColor)
+	a.grid.SetBordersColor(bordersColor)
+	if readOnly {
+		title += "[yellow::b]READ-ONLY[-::-] "
+	}
+	a.grid.SetTitle(title + "- " + strings.Join(help, " | ") + " ")
+}
+
+// handleResourceListKey handles keys that act on the resource table and its highlighted row
+func (a *App) handleResourceListKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+
+	// Digits sort by the matching column
+	if a.ResourceList.handleSortKey(event.Rune()) {
+		return nil
+	}
+	if !a.viewingContainers {
+		switch event.Rune() {
+		case 'w', 'W':
+			a.toggleWideColumns()
+			return nil
+		case 'y', 'Y':
+			a.showManifest()
+			return nil
+		case 'e', 'E':
+			a.editSelected()
+			return nil
+		case 'd', 'D':
+			a.describeSelected()
+			return nil
+		case 'l', 'L':
+			a.showWorkloadLogs()
+			return nil
+		case 'f', 'F':
+			a.showPortForwardPrompt()
+			return nil
+		case 's', 'S':
+			a.showScalePrompt()
+			return nil
+		case 'z', 'Z':
+			a.toggleScaleToZero()
+			return nil
+		case 'o', 'O':
+			a.showRolloutMenu()
+			return nil
+		case 'v', 'V':
+			a.diffLastApplied()
+			return nil
+		}
+		return event
+	}
+
+	switch event.Rune() {
+	case 'x', 'X':
+		if a.ResourceList.ItemCount() == 0 {
+			return nil
+		}
+		if err := a.ExecShell(a.ResourceList.SelectedKey()); err != nil {
+			a.showError(fmt.Sprintf("Error executing shell: %v", err))
+		}
+		return nil
+	}
+
+	return event
+}
+
 // updateGridLayout updates the grid column layout to utilize full terminal width
 func (a *App) updateGridLayout(grid *tview.Grid) {
 	_, _, width, _ := grid.GetRect()
 	
 	// Calculate optimal column widths for 3-column layout
+	var resourceWidth int
 	switch {
 	case width < 80: // Narrow terminal
 		// Equal distribution for 3 columns
 		colWidth := width / 3
-		grid.SetColumns(colWidth, colWidth, width-2*colWidth)
+		resourceWidth = width - 2*colWidth
+		grid.SetColumns(colWidth, colWidth, resourceWidth)
 	case width < 120: // Medium width terminal
 		// Proportional distribution
 		col1 := width / 4   // Namespaces
 		col2 := width / 3   // Resource Types
 		col3 := width - col1 - col2  // Resources
+		resourceWidth = col3
 		grid.SetColumns(col1, col2, col3)
 	default: // Wide terminal (120+)
 		// Optimal distribution for wide screens
 		col1 := max(20, width/5)     // Namespaces
 		col2 := max(25, width/4)     // Resource Types
 		col3 := width - col1 - col2 - 5 // Resources (remaining space)
+		resourceWidth = col3
 		grid.SetColumns(col1, col2, col3)
 	}
+
+	// Shrink the resource table columns to the pane, inside its border
+	if resourceWidth > 2 {
+		a.ResourceList.FitWidth(resourceWidth - 2)
+	}
 }
 
 // showLogsWindow shows or hides the logs window based on context
 func (a *App) showLogsWindow(show bool) {
 	if show {
 		// Show logs window
-		a.grid.AddItem(a.LogsView, 1, 2, 1, 1, 0, 0, false)
+		a.grid.AddItem(a.logsPane, 1, 2, 1, 1, 0, 0, false)
 	} else {
 		// Hide logs window - expand info view to full width
 		a.grid.AddItem(a.InfoView, 1, 0, 1, 3, 0, 0, false)
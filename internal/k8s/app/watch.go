@@ -0,0 +1,290 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchSyncTimeout bounds how long a view waits for the initial list before showing an error
+const watchSyncTimeout = 30 * time.Second
+
+// renderFunc prepares the view of a set of objects and returns the function that fills the
+// view on the UI goroutine. It runs on the UI goroutine for the initial contents, so it must
+// not wait for the network.
+type renderFunc func(objects []runtime.Object) (fill func())
+
+// resourceWatch is a running informer that keeps a list view up to date
+type resourceWatch struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	dynamic   bool
+	informer  cache.SharedIndexInformer
+	redraw    func(fill func()) // Runs fill, restoring the view's selection afterwards
+	stopCh    chan struct{}
+	refresh   chan struct{}
+	loaded    bool // The initial list was rendered; only used on the UI goroutine
+
+	mu     sync.Mutex
+	render renderFunc
+}
+
+// watchResources shows resources of gvr in namespace in the resource list and keeps
+// them up to date. The list is empty until the initial contents are rendered.
+func (a *App) watchResources(gvr schema.GroupVersionResource, namespace string, dynamic bool, render renderFunc) error {
+	// The table keeps its own selection across renders
+	w, err := a.startWatch(a.resourceWatch, gvr, namespace, dynamic, func(fill func()) { fill() }, render)
+	a.resourceWatch = w
+	return err
+}
+
+// startWatch returns a watch for gvr in namespace, reusing current when it already watches the
+// same resources. Otherwise current is stopped, and nil is returned on error. The view is
+// rendered empty while the initial list loads in the background, so that a slow API server
+// does not freeze the UI, then its contents and updates are rendered through redraw.
+func (a *App) startWatch(current *resourceWatch, gvr schema.GroupVersionResource, namespace string, dynamic bool, redraw func(fill func()), render renderFunc) (*resourceWatch, error) {
+	if current != nil && current.gvr == gvr && current.namespace == namespace && current.dynamic == dynamic && !current.stopped() {
+		current.setRender(render)
+		if current.loaded {
+			render(current.objects())()
+		}
+		return current, nil
+	}
+	current.stop()
+
+	informer, start, err := a.newInformer(gvr, namespace, dynamic)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &resourceWatch{
+		gvr:       gvr,
+		namespace: namespace,
+		dynamic:   dynamic,
+		informer:  informer,
+		redraw:    redraw,
+		render:    render,
+		stopCh:    make(chan struct{}),
+		refresh:   make(chan struct{}, 1),
+	}
+
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { w.notify() },
+		UpdateFunc: func(interface{}, interface{}) { w.notify() },
+		DeleteFunc: func(interface{}) { w.notify() },
+	})
+
+	listErrors := make(chan error, 1)
+	w.informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		select {
+		case listErrors <- err:
+		default:
+		}
+		cache.DefaultWatchErrorHandler(r, err)
+	})
+
+	start(w.stopCh)
+
+	a.showStatus(fmt.Sprintf("Loading %s...", gvr.Resource))
+	redraw(render(nil))
+	go a.syncWatch(w, listErrors)
+
+	return w, nil
+}
+
+// syncWatch waits for the initial list of w, then renders it on the UI goroutine and keeps
+// rendering its updates. A failed list stops w and is shown as an error, unless the view was
+// left in the meantime.
+func (a *App) syncWatch(w *resourceWatch, listErrors <-chan error) {
+	synced := make(chan struct{})
+	go func() {
+		if cache.WaitForCacheSync(w.stopCh, w.informer.HasSynced) {
+			close(synced)
+		}
+	}()
+
+	var err error
+	select {
+	case <-w.stopCh:
+		return
+	case <-synced:
+	case listErr := <-listErrors:
+		err = fmt.Errorf("error listing %s: %v", w.gvr.Resource, listErr)
+	case <-time.After(watchSyncTimeout):
+		err = fmt.Errorf("timed out listing %s", w.gvr.Resource)
+	}
+
+	var fill func()
+	if err == nil {
+		fill = w.getRender()(w.objects())
+	}
+	a.queueUpdateDraw(func() {
+		if w.stopped() {
+			return
+		}
+		if a.StatusBar.GetText(true) == fmt.Sprintf(" Loading %s...", w.gvr.Resource) {
+			a.showStatus("")
+		}
+		if err != nil {
+			w.stop()
+			a.showError(err.Error())
+			return
+		}
+		w.loaded = true
+		w.redraw(fill)
+	})
+	if err == nil {
+		a.runWatch(w)
+	}
+}
+
+// newInformer creates an informer for gvr in namespace along with the function that starts it.
+// Dynamic informers yield unstructured objects, the others typed API objects.
+func (a *App) newInformer(gvr schema.GroupVersionResource, namespace string, dynamic bool) (cache.SharedIndexInformer, func(<-chan struct{}), error) {
+	if dynamic {
+		if a.DynamicClient == nil {
+			return nil, nil, fmt.Errorf("dynamic client not initialized")
+		}
+		factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(a.DynamicClient, 0, namespace, nil)
+		return factory.ForResource(gvr).Informer(), factory.Start, nil
+	}
+
+	if a.KubeClient == nil {
+		return nil, nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(a.KubeClient, 0, informers.WithNamespace(namespace))
+	genericInformer, err := factory.ForResource(gvr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error watching %s: %v", gvr.Resource, err)
+	}
+	return genericInformer.Informer(), factory.Start, nil
+}
+
+// runWatch re-renders the view of w whenever its informer reports a change. Rendering is
+// prepared on this goroutine and the view is filled on the UI goroutine.
+func (a *App) runWatch(w *resourceWatch) {
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-w.refresh:
+			fill := w.getRender()(w.objects())
+			a.queueUpdateDraw(func() {
+				if !w.stopped() {
+					w.redraw(fill)
+				}
+			})
+		}
+	}
+}
+
+// notify marks the view of w dirty. Any change only does so, so bursts of events cause a
+// single redraw.
+func (w *resourceWatch) notify() {
+	select {
+	case w.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// setRender replaces how the view of w is rendered
+func (w *resourceWatch) setRender(render renderFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.render = render
+}
+
+// getRender returns how the view of w is rendered
+func (w *resourceWatch) getRender() renderFunc {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.render
+}
+
+// objects returns the cached objects of w sorted by namespace and name
+func (w *resourceWatch) objects() []runtime.Object {
+	items := w.informer.GetStore().List()
+	objects := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(runtime.Object); ok {
+			objects = append(objects, obj)
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		a, errA := meta.Accessor(objects[i])
+		b, errB := meta.Accessor(objects[j])
+		if errA != nil || errB != nil {
+			return false
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	return objects
+}
+
+// stopped reports whether w was stopped
+func (w *resourceWatch) stopped() bool {
+	select {
+	case <-w.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop stops the informer of w. It is safe to call on a nil or stopped watch.
+func (w *resourceWatch) stop() {
+	if w == nil {
+		return
+	}
+	select {
+	case <-w.stopCh:
+	default:
+		close(w.stopCh)
+	}
+}
+
+// stopWatches stops every informer feeding the UI
+func (a *App) stopWatches() {
+	a.stopResourceWatch()
+	a.namespaceWatch.stop()
+	a.namespaceWatch = nil
+}
+
+// stopResourceWatch stops the informer feeding the resource list
+func (a *App) stopResourceWatch() {
+	a.resourceWatch.stop()
+	a.resourceWatch = nil
+	a.stopTableWatch()
+}
+
+// preserveSelection rebuilds list with fill while keeping the highlighted item and scroll position
+func preserveSelection(list *tview.List, fill func()) {
+	selected := ""
+	if list.GetItemCount() > 0 {
+		selected, _ = list.GetItemText(list.GetCurrentItem())
+	}
+	offset, horizontal := list.GetOffset()
+
+	fill()
+
+	list.SetOffset(offset, horizontal)
+	for i := 0; i < list.GetItemCount(); i++ {
+		if main, _ := list.GetItemText(i); main == selected {
+			list.SetCurrentItem(i)
+			break
+		}
+	}
+}